@@ -4,15 +4,71 @@
 */
 package main
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
-func InjectNativeFunc() {
-	currEnv.Define("clock", NewClock())
+func InjectNativeFunc() { // 本地方法定义在全局作用域的父作用域中 用户代码可以定义同名变量遮蔽
+	env := NewEnvironment()
+	env.Define("clock", NewClock())
+	env.Define("bigint", NewNativeFunc("bigint", 1, func(args []any) any {
+		return ParseBig(args[0])
+	}))
+	env.Define("decimal", NewNativeFunc("decimal", 1, func(args []any) any {
+		return ParseDec(args[0])
+	}))
+	env.Define("str", NewNativeFunc("str", 1, func(args []any) any {
+		return ToString(args[0])
+	}))
+	env.Define("set", NewVarNativeFunc("set", 0, -1, func(args []any) any {
+		res := NewHashSet()
+		for _, arg := range args {
+			res.Add(callToken, arg)
+		}
+		return res
+	}))
+	InjectReflectFunc(env)
+	currEnv.Parent = env
 }
 
 type ICall interface { // 可被调用的函数
 	Call(args []any) any
-	ArgsSize() int
+	ArgsSize() *Arity
+}
+
+type INamedCall interface { // 支持具名参数调用
+	ICall
+	ParamNames() []string
+}
+
+type Missing struct { // 未传递的参数占位
+}
+
+var (
+	MissingArg = &Missing{}
+)
+
+type Arity struct { // 参数数量范围 Max < 0 表示不限数量
+	Min, Max int
+}
+
+func NewArity(min int, max int) *Arity {
+	return &Arity{Min: min, Max: max}
+}
+
+func (a *Arity) Accept(size int) bool {
+	return size >= a.Min && (a.Max < 0 || size <= a.Max)
+}
+
+func (a *Arity) String() string {
+	if a.Max < 0 {
+		return fmt.Sprintf("at least %d", a.Min)
+	}
+	if a.Min == a.Max {
+		return fmt.Sprintf("%d", a.Min)
+	}
+	return fmt.Sprintf("%d to %d", a.Min, a.Max)
 }
 
 type Clock struct { // 本地方法 获取时间
@@ -22,49 +78,128 @@ func NewClock() *Clock {
 	return &Clock{}
 }
 
-func (c *Clock) Call(args []any) any { // 数字只支持小数
-	return float64(time.Now().Unix())
+func (c *Clock) Call(args []any) any { // 秒级时间戳为整数
+	return time.Now().Unix()
+}
+
+func (c *Clock) ArgsSize() *Arity {
+	return NewArity(0, 0)
+}
+
+func (c *Clock) String() string {
+	return "<native fn clock>"
+}
+
+type NativeFunc struct { // 通用本地方法
+	Name  string
+	Arity *Arity
+	Func  func(args []any) any
+}
+
+func NewNativeFunc(name string, size int, func0 func(args []any) any) *NativeFunc { // 固定参数数量
+	return &NativeFunc{Name: name, Arity: NewArity(size, size), Func: func0}
 }
 
-func (c *Clock) ArgsSize() int {
-	return 0
+func NewVarNativeFunc(name string, min int, max int, func0 func(args []any) any) *NativeFunc { // 可变参数数量 max < 0 表示不限
+	return &NativeFunc{Name: name, Arity: NewArity(min, max), Func: func0}
 }
 
+func (n *NativeFunc) Call(args []any) any {
+	return n.Func(args)
+}
+
+func (n *NativeFunc) ArgsSize() *Arity {
+	return n.Arity
+}
+
+func (n *NativeFunc) String() string {
+	return fmt.Sprintf("<native fn %s>", n.Name)
+}
+
+var (
+	callToken *Token // 当前调用的位置 本地方法报错时使用 与 currEnv 一样在调用时切换
+)
+
 const (
 	RETURN_KEY = "$RETURN_KEY$"
+	OWNER_KEY  = "$OWNER_KEY$" // 方法定义所在的类 用于私有成员访问检查
 )
 
 type BaseCall struct { // 相当与一种新的类型
 	// 定义改函数时的 env 函数调用时不应该使用函数调用时的env
 	// 那样会访问到函数使用外的变量 应该使用函数定义时的环境，顺便实现闭包的功能
 	DefineEnv *Environment
-	Params    []*Token
-	Body      IStmt
+	Decl      *Function // 函数定义
+	Init      bool      // 类的 init 方法 总是返回 this
 }
 
 func (b *BaseCall) Call(args []any) any {
 	oldEnv := currEnv
 	currEnv = NewEnvironmentWithParent(b.DefineEnv) // 添加作用域
-	for i := 0; i < len(b.Params); i++ {            // 绑定参数
-		currEnv.Define(b.Params[i].Lexeme, args[i])
+	// 绑定参数 默认值在函数作用域中求值 可以引用前面的参数
+	for i, param := range b.Decl.Params {
+		var val any = MissingArg
+		if i < len(args) {
+			val = args[i]
+		}
+		if val == MissingArg {
+			if b.Decl.Defaults[i] == nil {
+				panic(fmt.Sprintf("func %v missing arg %s", b, param.Lexeme))
+			}
+			val = b.Decl.Defaults[i].GetValue()
+		}
+		currEnv.Define(param.Lexeme, val)
+	}
+	if b.Decl.Rest != nil { // 剩余参数打包为列表
+		rest := make([]any, 0)
+		if len(args) > len(b.Decl.Params) {
+			rest = append(rest, args[len(b.Decl.Params):]...)
+		}
+		currEnv.Define(b.Decl.Rest.Lexeme, NewList(rest))
 	}
 	currEnv.Define(RETURN_KEY, nil) // 预定义返回值
-	b.Body.Exec()                   // 执行函数体
+	b.Decl.Body.Exec()              // 执行函数体
 	res := currEnv.Get(RETURN_KEY)  // 获取返回值 必须在移除作用域前
-	currEnv = oldEnv                // 移除作用域
+	if b.Init {
+		res = currEnv.Get("this")
+	}
+	currEnv = oldEnv // 移除作用域
 	return res
 }
 
-func (b *BaseCall) ArgsSize() int {
-	return len(b.Params)
+func (b *BaseCall) ArgsSize() *Arity { // 有默认值的参数可以不传
+	min := 0
+	for _, default0 := range b.Decl.Defaults {
+		if default0 == nil {
+			min++
+		}
+	}
+	if b.Decl.Rest != nil {
+		return NewArity(min, -1)
+	}
+	return NewArity(min, len(b.Decl.Params))
 }
 
-func (b *BaseCall) BindThis(this *BaseInstance) *BaseCall {
+func (b *BaseCall) ParamNames() []string {
+	res := make([]string, 0, len(b.Decl.Params))
+	for _, param := range b.Decl.Params {
+		res = append(res, param.Lexeme)
+	}
+	return res
+}
+
+func (b *BaseCall) String() string {
+	return fmt.Sprintf("<fn %s>", b.Decl.Name.Lexeme)
+}
+
+func (b *BaseCall) BindThis(this any) *BaseCall { // 实例方法绑定实例 静态方法绑定类
 	env := NewEnvironmentWithParent(b.DefineEnv)
 	env.Define("this", this) // 创建新的作用域并添加 this 变量
-	return NewBaseCall(b.Params, b.Body, env)
+	res := NewBaseCall(b.Decl, env)
+	res.Init = b.Init
+	return res
 }
 
-func NewBaseCall(params []*Token, body IStmt, defineEnv *Environment) *BaseCall {
-	return &BaseCall{Params: params, Body: body, DefineEnv: defineEnv}
+func NewBaseCall(decl *Function, defineEnv *Environment) *BaseCall {
+	return &BaseCall{Decl: decl, DefineEnv: defineEnv}
 }
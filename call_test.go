@@ -0,0 +1,35 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestVariadic(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "rest", Source: `func log(level, ...parts) { print level; print parts; } log(1); log(1, 2, 3);`, Output: "1\n[]\n1\n[2, 3]\n"},
+		{Name: "spread", Source: `func sum(...xs) { var s = 0; for (var x in xs) { s = s + x; } return s; } var xs = [1, 2, 3]; print sum(...xs); print sum(0, ...xs, 4);`,
+			Output: "6\n10\n"},
+		{Name: "spread fixed", Source: "func f(a, b) { return a + b; }\nprint f(...[1, 2]);\nf(...[1]);",
+			Output: "3\n", Err: "[line 3] runtime error : func <fn f> args not match 1, need 2"},
+		{Name: "at least", Source: "func f(a, ...r) {}\nf();", Err: "[line 2] runtime error : func <fn f> args not match 0, need at least 1"},
+		{Name: "native", Source: `print clock(1);`, Err: "func <native fn clock> args not match 1, need 0"},
+	})
+}
+
+func TestDefault(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "default", Source: `func connect(host, port = 8080) { return host + ":" + str(port); } print connect("a"); print connect("b", 1);`,
+			Output: "a:8080\nb:1\n"},
+		{Name: "earlier param", Source: `func f(a, b = a * 2) { return b; } print f(3); print f(3, 1);`, Output: "6\n1\n"},
+		{Name: "range", Source: "func f(a, b = 2) {}\nf(1, 2, 3);", Err: "[line 2] runtime error : func <fn f> args not match 3, need 1 to 2"},
+		{Name: "order", Source: "func f(a = 1, b) {}", Err: "[line 1] param b without default follows default param"},
+		{Name: "named", Source: `func connect(host, port = 8080) { return host + ":" + str(port); } print connect(host: "b", port: 1); print connect("c", port: 2); print connect(port: 3, host: "d");`,
+			Output: "b:1\nc:2\nd:3\n"},
+		{Name: "constructor", Source: `class C { init(x, y = 1) { this.s = x + y; } } print C(1).s; print C(y: 3, x: 2).s;`, Output: "2\n5\n"},
+		{Name: "multiple values", Source: "func f(a) {}\nf(1, a: 2);", Err: "[line 2] runtime error : <fn f> got multiple values for param a"},
+		{Name: "unknown name", Source: "func f(a) {}\nf(b: 2);", Err: "[line 2] runtime error : <fn f> has no param named b"},
+		{Name: "missing", Source: "func f(a, b) {}\nf(b: 2);", Err: "func <fn f> missing arg a"},
+	})
+}
@@ -4,74 +4,391 @@
 */
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
-type BaseClass struct { // 基础类信息  类存储方法信息
+type MethodTable struct { // 类与 trait 共用的方法表
 	Name    string
-	Parent  *BaseClass
 	Methods map[string]*BaseCall
 }
 
+type BaseClass struct { // 基础类信息  类存储方法信息
+	MethodTable
+	Parent        *BaseClass
+	Traits        []*BaseTrait         // with 混入的 trait
+	Linear        []*MethodTable       // 方法查找顺序 类本身 trait(后声明的优先) 父类的查找顺序
+	StaticMethods map[string]*BaseCall // 静态方法 this 绑定为类
+	StaticFields  map[string]any
+	Getters       map[string]*BaseCall // 读取属性时调用
+	Setters       map[string]*BaseCall // 给属性赋值时调用
+	Abstract      bool                 // 抽象类不能实例化
+	Abstracts     map[string]*Function // 抽象方法 只有签名
+	Interfaces    []*BaseInterface     // implements 声明的接口
+	FieldDecls    []*Var               // 类中声明的实例字段 创建实例时在 init 前初始化
+	DefineEnv     *Environment         // 字段初始化表达式求值的环境
+	Strict        bool                 // 严格模式 不能给未声明的字段赋值
+	ValueFields   []string             // 值类型的字段 不为 nil 时使用生成的构造方法 按字段比较与哈希
+	Variants      []any                // 枚举的成员 按声明顺序 没有字段的为实例 有字段的为类
+}
+
 func (b *BaseClass) Call(args []any) any { // 把类当作方法调用就是创建对象
+	if b.Abstract {
+		panic(fmt.Sprintf("can't instantiate abstract class %s", b.Name))
+	}
 	res := NewBaseInstance(b)
-	if init, ok := b.Methods["init"]; ok { // 若存在初始化方法调用初始化方法，参数透传，也就是参数必须与init方法参数一致
+	b.InitFields(res)
+	if b.ValueFields != nil { // 生成的构造方法 参数按顺序赋值给字段
+		for i, name := range b.ValueFields {
+			if args[i] == MissingArg {
+				panic(fmt.Sprintf("%s missing arg %s", b.Name, name))
+			}
+			res.Fields[name] = args[i]
+		}
+		return res
+	}
+	if init := b.FindMethod("init"); init != nil { // 若存在初始化方法调用初始化方法，参数透传，也就是参数必须与init方法参数一致 没有时使用继承的
 		init.BindThis(res).Call(args)
 	}
 	return res
 }
 
-func (b *BaseClass) ArgsSize() int {
-	if init, ok := b.Methods["init"]; ok { // 若存在初始化方法，把改类当方法调用时参数数量必须一致
+func (b *BaseClass) InitFields(inst *BaseInstance) { // 先初始化父类字段 初始化表达式中 this 为实例
+	if b.Parent != nil {
+		b.Parent.InitFields(inst)
+	}
+	if len(b.FieldDecls) == 0 {
+		return
+	}
+	oldEnv := currEnv
+	currEnv = NewEnvironmentWithParent(b.DefineEnv)
+	currEnv.Define("this", inst)
+	for _, field := range b.FieldDecls {
+		var val any
+		if field.Expr != nil {
+			val = field.Expr.GetValue()
+		}
+		if field.Name.Type == PRIVATE { // 私有字段按声明的类分开存储
+			inst.Privates(b)[field.Name.Lexeme] = val
+		} else {
+			inst.Fields[field.Name.Lexeme] = val
+		}
+	}
+	currEnv = oldEnv
+}
+
+func (b *BaseClass) HasField(name string) bool { // 沿继承链查找字段声明
+	for class := b; class != nil; class = class.Parent {
+		for _, field := range class.FieldDecls {
+			if field.Name.Lexeme == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (b *BaseClass) DeclaresField(name string) bool { // 只查找当前类 私有字段不继承
+	for _, field := range b.FieldDecls {
+		if field.Name.Lexeme == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BaseClass) IsSubclassOf(other *BaseClass) bool { // 包含自身
+	for class := b; class != nil; class = class.Parent {
+		if class == other {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BaseClass) IsStrict() bool { // 继承链上任意一个类为严格模式
+	for class := b; class != nil; class = class.Parent {
+		if class.Strict {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BaseClass) ArgsSize() *Arity {
+	if b.ValueFields != nil {
+		return NewArity(len(b.ValueFields), len(b.ValueFields))
+	}
+	if init := b.FindMethod("init"); init != nil { // 若存在初始化方法，把改类当方法调用时参数数量必须一致
 		return init.ArgsSize()
 	}
-	return 0
+	return NewArity(0, 0)
+}
+
+func (b *BaseClass) ParamNames() []string { // 具名参数透传给 init
+	if b.ValueFields != nil {
+		return b.ValueFields
+	}
+	if init := b.FindMethod("init"); init != nil {
+		return init.ParamNames()
+	}
+	return make([]string, 0)
 }
 
 func (b *BaseClass) GetMethod(name string) *BaseCall {
-	if val, ok := b.Methods[name]; ok {
-		return val
+	if method := b.FindMethod(name); method != nil {
+		return method
+	}
+	panic(fmt.Sprintf("no method name %s", name))
+}
+
+func (b *BaseClass) FindMethod(name string) *BaseCall { // 按线性化顺序查找 不存在返回 nil
+	return FindInLinear(b.Linear, name)
+}
+
+func FindInLinear(linear []*MethodTable, name string) *BaseCall {
+	for _, table := range linear {
+		if val, ok := table.Methods[name]; ok {
+			return val
+		}
+	}
+	return nil
+}
+
+func (b *BaseClass) Linearize() { // 计算方法查找顺序 重复出现的 trait 只保留最后一次
+	linear := []*MethodTable{&b.MethodTable}
+	for i := len(b.Traits) - 1; i >= 0; i-- {
+		linear = append(linear, &b.Traits[i].MethodTable)
 	}
 	if b.Parent != nil {
-		return b.Parent.GetMethod(name)
+		linear = append(linear, b.Parent.Linear...)
+	}
+	b.Linear = make([]*MethodTable, 0, len(linear))
+	for i, table := range linear {
+		repeat := false
+		for _, other := range linear[i+1:] {
+			if other == table {
+				repeat = true
+				break
+			}
+		}
+		if !repeat {
+			b.Linear = append(b.Linear, table)
+		}
 	}
-	panic(fmt.Sprintf("no method name %s", name))
+}
+
+// CheckTraitConflict 多个 trait 提供同名方法且类本身没有覆盖时报错
+// 后声明的 trait 通过 super 调用同名方法时是叠加而不是冲突
+func (b *BaseClass) CheckTraitConflict() {
+	owners := make(map[string]string)
+	for _, trait := range b.Traits {
+		for name, method := range trait.Methods {
+			if _, ok := b.Methods[name]; ok {
+				continue
+			}
+			if owner, ok := owners[name]; ok && owner != trait.Name && !method.Decl.CallsSuper(name) {
+				panic(fmt.Sprintf("class %s method %s conflict between trait %s and %s", b.Name, name, owner, trait.Name))
+			}
+			owners[name] = trait.Name
+		}
+	}
+}
+
+func (b *BaseClass) CheckAbstract() { // 具体类必须实现父类的抽象方法与所有接口方法 参数数量需要兼容
+	if b.Abstract {
+		return
+	}
+	requirements := make(map[string]*Requirement)
+	classes := make([]*BaseClass, 0)
+	for class := b; class != nil; class = class.Parent {
+		classes = append(classes, class)
+	}
+	for i := len(classes) - 1; i >= 0; i-- { // 从根类开始收集 子类的声明覆盖父类
+		for _, inter := range classes[i].Interfaces {
+			inter.CollectMethods(requirements)
+		}
+		for name, method := range classes[i].Abstracts {
+			requirements[name] = NewRequirement(method, classes[i].Name)
+		}
+	}
+	missing := make([]string, 0)
+	for name, requirement := range requirements {
+		method := b.FindMethod(name)
+		if method == nil {
+			missing = append(missing, fmt.Sprintf("%s.%s", requirement.Owner, name))
+		} else if size := len(requirement.Decl.Params); !method.ArgsSize().Accept(size) {
+			panic(fmt.Sprintf("class %s method %s must accept %d args as declared in %s", b.Name, name, size, requirement.Owner))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		panic(fmt.Sprintf("class %s must implement %s", b.Name, strings.Join(missing, ", ")))
+	}
+}
+
+func (b *BaseClass) FindGetter(name string) *BaseCall {
+	return b.FindMember(name, func(class *BaseClass) map[string]*BaseCall {
+		return class.Getters
+	})
+}
+
+func (b *BaseClass) FindSetter(name string) *BaseCall {
+	return b.FindMember(name, func(class *BaseClass) map[string]*BaseCall {
+		return class.Setters
+	})
+}
+
+func (b *BaseClass) FindMember(name string, members func(class *BaseClass) map[string]*BaseCall) *BaseCall { // 沿继承链查找 子类优先
+	for class := b; class != nil; class = class.Parent {
+		if val, ok := members(class)[name]; ok {
+			return val
+		}
+	}
+	return nil
+}
+
+func (b *BaseClass) Get(name string) any { // 类本身也可以当作实例访问静态成员，静态成员可以继承
+	if owner := b.FindStaticField(name); owner != nil { // 先找字段
+		return owner.StaticFields[name]
+	}
+	method := b.FindMember(name, func(class *BaseClass) map[string]*BaseCall { // 再找方法
+		return class.StaticMethods
+	})
+	if method != nil {
+		return method.BindThis(b)
+	}
+	panic(fmt.Sprintf("class %s no static member %s", b.Name, name))
+}
+
+func (b *BaseClass) Set(name *Token, val any) { // 总是写入当前类 子类赋值会遮蔽父类的静态字段而不修改父类
+	if b.Variants != nil { // 枚举的成员固定 不能修改或新增
+		RuntimeError(name, fmt.Sprintf("can't set member %s of enum %s", name.Lexeme, b.Name))
+	}
+	b.StaticFields[name.Lexeme] = val
+}
+
+func (b *BaseClass) FindStaticField(name string) *BaseClass { // 返回定义该静态字段的类 不存在返回 nil
+	for class := b; class != nil; class = class.Parent {
+		if _, ok := class.StaticFields[name]; ok {
+			return class
+		}
+	}
+	return nil
+}
+
+func (b *BaseClass) Iterator() IIterator { // 只有枚举可以迭代 按声明顺序返回成员
+	if b.Variants == nil {
+		panic(fmt.Sprintf("class %s not iterable", b.Name))
+	}
+	return NewSliceIterator(b.Variants)
+}
+
+func (b *BaseClass) String() string {
+	return fmt.Sprintf("<class %s>", b.Name)
 }
 
 func NewBaseClass(name string, parent *BaseClass, methods map[string]*BaseCall) *BaseClass {
-	return &BaseClass{Name: name, Parent: parent, Methods: methods}
+	res := &BaseClass{MethodTable: MethodTable{Name: name, Methods: methods}, Parent: parent,
+		StaticMethods: make(map[string]*BaseCall), StaticFields: make(map[string]any),
+		Getters: make(map[string]*BaseCall), Setters: make(map[string]*BaseCall), Abstracts: make(map[string]*Function)}
+	res.Linearize()
+	return res
 }
 
 type IInstance interface {
 	Get(name string) any
-	Set(name string, val any)
+	Set(name *Token, val any) // name 用于报错定位
+}
+
+type IIndex interface { // 支持 obj[index] 读写
+	GetIndex(token *Token, index any) any // token 为 [ 用于报错定位
+	SetIndex(token *Token, index any, val any)
+}
+
+type IContains interface { // 支持 val in obj
+	Contains(token *Token, val any) bool // token 为 in 用于报错定位
 }
 
 type BaseInstance struct { // 实例存储字段信息
 	Class  *BaseClass
 	Fields map[string]any
+	Owners map[*BaseClass]map[string]any // 定义私有字段的类 -> 该类的私有字段 父子类的同名私有字段互不影响
 }
 
-func (b *BaseInstance) Set(name string, val any) {
-	b.Fields[name] = val // 存在覆盖，不存在创建
+func (b *BaseInstance) Set(name *Token, val any) {
+	if b.IsValue() { // 值类型不可修改 保证哈希稳定
+		RuntimeError(name, fmt.Sprintf("can't set field %s of %s, use with to copy", name.Lexeme, b.Class.Name))
+	}
+	if setter := b.Class.FindSetter(name.Lexeme); setter != nil { // 先找 setter
+		setter.BindThis(b).Call([]any{val})
+		return
+	}
+	if b.Class.FindGetter(name.Lexeme) != nil {
+		RuntimeError(name, fmt.Sprintf("property %s of %s has getter but no setter", name.Lexeme, b.Class.Name))
+	}
+	if b.Class.IsStrict() && !b.Class.HasField(name.Lexeme) {
+		RuntimeError(name, fmt.Sprintf("strict class %s has no field %s, declare it with var", b.Class.Name, name.Lexeme))
+	}
+	b.Fields[name.Lexeme] = val // 存在覆盖，不存在创建
 }
 
 func (b *BaseInstance) Get(name string) any {
-	if val, ok := b.Fields[name]; ok { // 先找字段
+	if getter := b.Class.FindGetter(name); getter != nil { // 先找 getter
+		return getter.BindThis(b).Call(nil)
+	}
+	if val, ok := b.Fields[name]; ok { // 再找字段
 		return val
 	}
-	if method := b.Class.GetMethod(name); method != nil { // 再找方法
+	if method := b.Class.FindMethod(name); method != nil { // 再找方法
 		return method.BindThis(b)
 	}
+	if name == "with" && b.IsValue() { // 值类型生成的复制方法
+		return NewWithCall(b)
+	}
 	panic(fmt.Sprintf("field %v not Define", name))
 }
 
-func (b *BaseInstance) GetSuperMethod(method string) any {
-	if b.Class.Parent == nil {
-		panic(fmt.Sprintf("class %v no parent", b.Class))
+func (b *BaseInstance) GetSuper(name *Token, owner *MethodTable, parent *BaseClass) any { // 从方法定义所在的类或 trait 之后按线性化顺序查找
+	for i, table := range b.Class.Linear {
+		if table != owner {
+			continue
+		}
+		if method := FindInLinear(b.Class.Linear[i+1:], name.Lexeme); method != nil {
+			return method.BindThis(b)
+		}
+		break
+	}
+	if parent != nil { // 再找父类的 getter 与字段
+		if getter := parent.FindGetter(name.Lexeme); getter != nil {
+			return getter.BindThis(b).Call(nil)
+		}
+	}
+	if val, ok := b.Fields[name.Lexeme]; ok && name.Type != PRIVATE {
+		return val
+	}
+	RuntimeError(name, fmt.Sprintf("no super member %s after %s", name.Lexeme, owner.Name))
+	return nil
+}
+
+func (b *BaseClass) GetSuper(name *Token, parent *BaseClass) any { // 静态方法中的 super 访问父类的静态成员 this 仍为当前类
+	if parent == nil {
+		RuntimeError(name, fmt.Sprintf("class %s no parent", b.Name))
+	}
+	if owner := parent.FindStaticField(name.Lexeme); owner != nil {
+		return owner.StaticFields[name.Lexeme]
+	}
+	method := parent.FindMember(name.Lexeme, func(class *BaseClass) map[string]*BaseCall {
+		return class.StaticMethods
+	})
+	if method == nil {
+		RuntimeError(name, fmt.Sprintf("no super static member %s in %s", name.Lexeme, parent.Name))
 	}
-	return b.Class.Parent.GetMethod(method)
+	return method.BindThis(b)
 }
 
 func NewBaseInstance(class *BaseClass) *BaseInstance {
-	return &BaseInstance{Class: class, Fields: make(map[string]any)}
+	return &BaseInstance{Class: class, Fields: make(map[string]any), Owners: make(map[*BaseClass]map[string]any)}
 }
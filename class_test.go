@@ -0,0 +1,93 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestStatic(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "method and field", Source: `class M { static var PI = 3; static square(x) { return x * x; } } print M.square(3); print M.PI;`,
+			Output: "9\n3\n"},
+		{Name: "inherited", Source: `class A { static var PI = 3; static name() { return className(this); } } class B < A {} print B.PI; print B.name();`,
+			Output: "3\nB\n"},
+		{Name: "subclass assign", Source: `class A { static var PI = 3; } class B < A {} B.PI = 4; print A.PI; print B.PI;`,
+			Output: "3\n4\n"},
+		{Name: "parent assign", Source: `class A { static var PI = 3; } class B < A {} A.PI = 5; print B.PI;`, Output: "5\n"},
+		{Name: "static this", Source: `class C { static var n = 0; static inc() { this.n = this.n + 1; return this.n; } } C.inc(); print C.inc();`,
+			Output: "2\n"},
+	})
+}
+
+func TestAbstract(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "implemented", Source: `abstract class Shape { abstract area(); twice() { return this.area() * 2; } } class Sq < Shape { init(s) { this.s = s; } area() { return this.s * this.s; } } print Sq(3).twice();`,
+			Output: "18\n"},
+		{Name: "instantiate", Source: `abstract class Shape { abstract area(); } Shape();`, Err: "can't instantiate abstract class Shape"},
+		{Name: "missing", Source: `abstract class Shape { abstract area(); } class Bad < Shape {}`, Err: "class Bad must implement Shape.area"},
+		{Name: "non-abstract class", Source: `class Shape { abstract area(); }`, Err: "[line 1] abstract method area in non-abstract class Shape"},
+	})
+}
+
+func TestInterface(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "implemented", Source: `interface Named { name(); } class Dog implements Named { name() { return "dog"; } } print Dog().name(); print Dog() is Named;`,
+			Output: "dog\ntrue\n"},
+		{Name: "missing", Source: `interface Named { name(); } class Dog implements Named {}`, Err: "class Dog must implement Named.name"},
+		{Name: "arity", Source: `interface Sized { size(n); } class Box implements Sized { size() { return 1; } }`,
+			Err: "class Box method size must accept 1 args as declared in Sized"},
+		{Name: "parent interface", Source: `interface A { a(); } interface B < A { b(); } class C implements B { b() { return 1; } }`,
+			Err: "class C must implement A.a"},
+		{Name: "not an interface", Source: "var x = 1;\nclass C implements x {}", Err: "[line 2] runtime error : x not an interface"},
+	})
+}
+
+func TestField(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "per instance", Source: `class P { var count = 0; var items = []; } var a = P(); var b = P(); a.items.push(1); print a.count; print b.items;`,
+			Output: "0\n[]\n"},
+		{Name: "parent first", Source: `class A { var x = 1; } class B < A { var y = this.x + 1; init() { print this.y; } } B();`, Output: "2\n"},
+		{Name: "strict", Source: "strict class S { var a = 1; } var s = S(); s.a = 2; print s.a;\ns.b = 3;",
+			Output: "2\n", Err: "[line 2] runtime error : strict class S has no field b, declare it with var"},
+		{Name: "strict inherited", Source: `strict class S { var a = 1; } class T < S { var c = 0; } var t = T(); t.c = 5; print t.c; t.d = 1;`,
+			Output: "5\n", Err: "strict class T has no field d, declare it with var"},
+	})
+}
+
+func TestInit(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "returns this", Source: `class I { init(n) { this.n = n; } } var i = I(1); print i.init(2) == i; print i.n;`, Output: "true\n2\n"},
+		{Name: "return value", Source: `class I { init() { return 1; } }`, Err: "[line 1] can't return a value from an initializer"},
+		{Name: "getter in init", Source: `class O { init() { class X { foo { return 1; } } print X().foo; } } O();`, Output: "1\n"},
+		{Name: "inherited arity", Source: `class A { init(a, b) { this.s = a + b; } } class B < A {} print B(1, 2).s; B(1);`,
+			Output: "3\n", Err: "func <class B> args not match 1, need 2"},
+		{Name: "super init", Source: `class A { init(a) { this.a = a; } } class B < A { init(a, b) { super.init(a); this.b = b; } } var b = B(1, 2); print b.a + b.b;`,
+			Output: "3\n"},
+	})
+}
+
+func TestSuper(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "three levels", Source: `class A { m() { return "A"; } } class B < A { m() { return "B" + super.m(); } } class C < B { m() { return "C" + super.m(); } } print C().m();`,
+			Output: "CBA\n"},
+		{Name: "bound", Source: `class A { m() { return this.v; } } class B < A { init() { this.v = 7; } f() { var g = super.m; return g(); } } print B().f();`,
+			Output: "7\n"},
+		{Name: "nested func", Source: `class A { m() { return "A"; } } class B < A { m() { func inner() { return super.m(); } return inner(); } } print B().m();`,
+			Output: "A\n"},
+		{Name: "no parent", Source: `class A { m() { return super.m(); } }`, Err: "[line 1] super used outside a subclass method"},
+		{Name: "outside class", Source: `func f() { return super.x; }`, Err: "[line 1] super used outside a subclass method"},
+		{Name: "missing member", Source: "class A {}\nclass B < A { m() { return super.nope(); } }\nB().m();", Err: "[line 2] runtime error : no super member nope after B"},
+	})
+}
+
+func TestProperty(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "getter and setter", Source: `class R { init(w, h) { this.w = w; this.h = h; } area { return this.w * this.h; } set width(v) { this.w = v; } } var r = R(2, 3); print r.area; r.width = 5; print r.area; print r.w;`,
+			Output: "6\n15\n5\n"},
+		{Name: "inherited and overridden", Source: `class A { area { return 1; } } class B < A {} class C < A { area { return super.area + 10; } } print B().area; print C().area;`,
+			Output: "1\n11\n"},
+		{Name: "no setter", Source: "class A { area { return 1; } }\nA().area = 2;", Err: "[line 2] runtime error : property area of A has getter but no setter"},
+		{Name: "setter params", Source: `class A { set w(a, b) {} }`, Err: "[line 1] setter w must have exactly one param"},
+	})
+}
@@ -22,35 +22,94 @@ type Binary struct { // 二元表达式
 func (b *Binary) GetValue() any {
 	left := b.Left.GetValue()
 	right := b.Right.GetValue()
+	_, lok := left.(*BaseInstance)
+	_, rok := right.(*BaseInstance)
+	if _, ok := OperatorMethods[b.Operator.Type]; ok && (lok || rok) && b.Operator.Type != ADD { // 运算符重载
+		return InstanceBinary(b.Operator, left, right)
+	}
 	switch b.Operator.Type {
-	case GT:
-		return left.(float64) > right.(float64)
-	case GE:
-		return left.(float64) >= right.(float64)
-	case LT:
-		return left.(float64) < right.(float64)
-	case LE:
-		return left.(float64) <= right.(float64)
-	case DIV:
-		return left.(float64) / right.(float64)
-	case MUL:
-		return left.(float64) * right.(float64)
-	case SUB:
-		return left.(float64) - right.(float64)
-	case ADD: // 字符串也可以相加
-		if _, ok := left.(string); ok {
-			return left.(string) + right.(string)
+	case GT, GE, LT, LE, DIV, MUL, SUB:
+		if !IsNumber(left) || !IsNumber(right) {
+			RuntimeError(b.Operator, fmt.Sprintf("operands of '%s' must be numbers, got %s and %s",
+				b.Operator.Lexeme, TypeName(left), TypeName(right)))
+		}
+		return NumOp(b.Operator, left, right)
+	case ADD: // 任意一侧为字符串时两侧都转换为文本拼接 左侧实例定义了 __add__ 时优先调用
+		if res, ok := CallOperator(left, "__add__", right); ok {
+			return res
+		}
+		_, lstr := left.(string)
+		_, rstr := right.(string)
+		if lstr || rstr {
+			return ToString(left) + ToString(right)
+		}
+		if lok || rok {
+			return InstanceBinary(b.Operator, left, right)
+		}
+		if !IsNumber(left) || !IsNumber(right) {
+			RuntimeError(b.Operator, fmt.Sprintf("operands of '+' must be numbers or strings, got %s and %s",
+				TypeName(left), TypeName(right)))
+		}
+		return NumOp(b.Operator, left, right)
+	case DOT2: // 0..n 整数区间
+		start, sok := left.(int64)
+		end, eok := right.(int64)
+		if !sok || !eok {
+			RuntimeError(b.Operator, fmt.Sprintf("operands of '..' must be ints, got %s and %s", TypeName(left), TypeName(right)))
 		}
-		return left.(float64) + right.(float64)
+		return NewRange(start, end)
+	case IN: // 列表 字典 集合判断包含 字符串判断子串 实例判断字段是否存在
+		return In(b.Operator, left, right)
+	case IS: // 实例是否属于类 trait 接口 或值的类型名
+		return Is(b.Operator, left, right)
 	case NE: // == != 可以应用到 数字 文本 布尔值上
-		return left != right
+		return !Equal(left, right)
 	case EQ:
-		return left == right
+		return Equal(left, right)
 	default:
 		panic(fmt.Sprintf("invalid TokenType %v to left %v right %v", b.Operator.Lexeme, left, right))
 	}
 }
 
+func In(operator *Token, left, right any) bool {
+	switch temp := right.(type) {
+	case IContains:
+		return temp.Contains(operator, left)
+	case string:
+		if str, ok := left.(string); ok {
+			return strings.Contains(temp, str)
+		}
+	case *BaseInstance: // 定义了 __contains__ 时调用 否则判断字段是否存在
+		if res, ok := CallOperator(temp, "__contains__", left); ok {
+			return MustBool("__contains__", res)
+		}
+		if name, ok := left.(string); ok { // 私有字段单独存储 不会被找到
+			_, has := temp.Fields[name]
+			return has
+		}
+	}
+	RuntimeError(operator, fmt.Sprintf("operator 'in' not supported for %s in %s", TypeName(left), TypeName(right)))
+	return false
+}
+
+func Equal(left, right any) bool {
+	if IsNumber(left) && IsNumber(right) { // 整数与小数按数值比较
+		return NumEqual(left, right)
+	}
+	if res, ok := CallOperator(left, "__eq__", right); ok { // 实例可以通过 __eq__ 自定义相等
+		return MustBool("__eq__", res)
+	}
+	if res, ok := CallOperator(right, "__eq__", left); ok {
+		return MustBool("__eq__", res)
+	}
+	linst, lok := left.(*BaseInstance)
+	rinst, rok := right.(*BaseInstance)
+	if lok && rok && linst.IsValue() && rinst.IsValue() { // 值类型按字段比较
+		return ValueEqual(linst, rinst)
+	}
+	return left == right
+}
+
 func (b *Binary) String() string {
 	return fmt.Sprintf("(%s %s %s)", b.Left, b.Operator.Lexeme, b.Right)
 }
@@ -98,6 +157,36 @@ func NewLiteral(token *Token) *Literal {
 	return &Literal{Token: token}
 }
 
+type Template struct { // "a${b}c" 各部分按 print 的规则转换为文本后拼接
+	Parts []IExpr
+}
+
+func (t *Template) GetValue() any {
+	buff := strings.Builder{}
+	for _, part := range t.Parts {
+		buff.WriteString(ToString(part.GetValue()))
+	}
+	return buff.String()
+}
+
+func (t *Template) String() string {
+	buff := strings.Builder{}
+	buff.WriteString("\"")
+	for _, part := range t.Parts {
+		if literal, ok := part.(*Literal); ok {
+			buff.WriteString(literal.Token.Lexeme)
+		} else {
+			buff.WriteString(fmt.Sprintf("${%s}", part))
+		}
+	}
+	buff.WriteString("\"")
+	return buff.String()
+}
+
+func NewTemplate(parts []IExpr) *Template {
+	return &Template{Parts: parts}
+}
+
 type Unary struct { // 一元表达式
 	Token *Token
 	Expr  IExpr
@@ -107,9 +196,18 @@ func (u *Unary) GetValue() any {
 	val := u.Expr.GetValue()
 	switch u.Token.Type {
 	case NOT:
+		if _, ok := val.(bool); !ok {
+			RuntimeError(u.Token, fmt.Sprintf("operand of '!' must be a bool, got %s", TypeName(val)))
+		}
 		return !val.(bool)
 	case SUB:
-		return -val.(float64)
+		if res, ok := CallOperator(val, "__neg__"); ok {
+			return res
+		}
+		if !IsNumber(val) {
+			RuntimeError(u.Token, fmt.Sprintf("operand of '-' must be a number, got %s", TypeName(val)))
+		}
+		return NumNeg(u.Token, val)
 	default:
 		panic(fmt.Sprintf("invalud TokenType %v apply %v", u.Token.Type, val))
 	}
@@ -149,31 +247,42 @@ func (l *Logical) String() string {
 }
 
 func (l *Logical) GetValue() any {
-	val := l.Left.GetValue().(bool)
+	what := fmt.Sprintf("operands of '%s'", l.Operator.Lexeme)
+	val := MustCond(l.Operator, l.Left.GetValue(), what)
 	switch l.Operator.Type {
 	case AND:
 		if !val {
 			return false
 		}
-		return l.Right.GetValue()
+		return MustCond(l.Operator, l.Right.GetValue(), what)
 	case OR:
 		if val {
 			return true
 		}
-		return l.Right.GetValue()
+		return MustCond(l.Operator, l.Right.GetValue(), what)
 	default:
 		panic(fmt.Sprintf("invalid Operator %s", l.Operator))
 	}
-	return nil
 }
 
 func NewLogical(left IExpr, right IExpr, operator *Token) *Logical {
 	return &Logical{Left: left, Right: right, Operator: operator}
 }
 
-type Call struct { // func(args...)
-	Caller IExpr   // id 调用变量
-	Args   []IExpr // 参数列表
+type Call struct { // func(args..., name: arg...)
+	Caller IExpr       // id 调用变量
+	Args   []IExpr     // 参数列表
+	Named  []*NamedArg // 具名参数列表
+	Token  *Token      // ( 用于报错定位
+}
+
+type NamedArg struct { // name: expr
+	Name *Token
+	Expr IExpr
+}
+
+func NewNamedArg(name *Token, expr IExpr) *NamedArg {
+	return &NamedArg{Name: name, Expr: expr}
 }
 
 func (c *Call) String() string {
@@ -184,6 +293,9 @@ func (c *Call) String() string {
 		buff.WriteString(arg.String())
 		buff.WriteString(",")
 	}
+	for _, arg := range c.Named {
+		buff.WriteString(fmt.Sprintf("%s: %s,", arg.Name.Lexeme, arg.Expr))
+	}
 	buff.WriteString(")")
 	return buff.String()
 }
@@ -194,18 +306,88 @@ func (c *Call) GetValue() any {
 		panic(fmt.Sprintf("%v can't callable", temp))
 	}
 	caller := temp.(ICall) // 获取调用对象
-	args := make([]any, 0, len(c.Args))
-	for _, arg := range c.Args {
-		args = append(args, arg.GetValue())
+	args := EvalArgs(c.Args)
+	if len(c.Named) > 0 { // 具名参数按参数名放到对应位置
+		args = BindNamedArgs(caller, args, c.Named)
+	}
+	if size := caller.ArgsSize(); !size.Accept(len(args)) { // 调用参数校验
+		RuntimeError(c.Token, fmt.Sprintf("func %v args not match %d, need %v", caller, len(args), size))
+	}
+	oldToken := callToken
+	callToken = c.Token
+	res := caller.Call(args) // 进行调用
+	callToken = oldToken
+	return res
+}
+
+func NewCall(caller IExpr, args []IExpr, named []*NamedArg, token *Token) *Call {
+	return &Call{Caller: caller, Args: args, Named: named, Token: token}
+}
+
+func BindNamedArgs(caller ICall, args []any, named []*NamedArg) []any { // 没有传递的位置使用 MissingArg 占位 由被调用方填充默认值
+	temp, ok := caller.(INamedCall)
+	if !ok {
+		RuntimeError(named[0].Name, fmt.Sprintf("%v does not accept named args", caller))
+	}
+	names := temp.ParamNames()
+	res := make([]any, len(names))
+	for i := 0; i < len(res); i++ {
+		res[i] = MissingArg
 	}
-	if len(args) != caller.ArgsSize() { // 调用参数校验
-		panic(fmt.Sprintf("func %v args not match %d != %d", caller, len(args), caller.ArgsSize()))
+	copy(res, args)
+	if len(args) > len(names) { // 多余的参数属于剩余参数
+		res = append(res, args[len(names):]...)
 	}
-	return caller.Call(args) // 进行调用
+	for _, arg := range named {
+		index := -1
+		for i, name := range names {
+			if name == arg.Name.Lexeme {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			RuntimeError(arg.Name, fmt.Sprintf("%v has no param named %s", caller, arg.Name.Lexeme))
+		}
+		if res[index] != MissingArg {
+			RuntimeError(arg.Name, fmt.Sprintf("%v got multiple values for param %s", caller, arg.Name.Lexeme))
+		}
+		res[index] = arg.Expr.GetValue()
+	}
+	return res
+}
+
+func EvalArgs(exprs []IExpr) []any { // 参数求值 并展开 ...xs
+	res := make([]any, 0, len(exprs))
+	for _, expr := range exprs {
+		if spread, ok := expr.(*Spread); ok {
+			iter := GetIterator(spread.Expr.GetValue())
+			for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+				res = append(res, item)
+			}
+		} else {
+			res = append(res, expr.GetValue())
+		}
+	}
+	return res
+}
+
+type Spread struct { // ...expr 只能出现在调用参数与列表字面量中
+	Expr  IExpr
+	Token *Token
+}
+
+func (s *Spread) String() string {
+	return fmt.Sprintf("...%s", s.Expr)
+}
+
+func (s *Spread) GetValue() any {
+	RuntimeError(s.Token, "spread only allowed in call args or list literal")
+	return nil
 }
 
-func NewCall(caller IExpr, args []IExpr) *Call {
-	return &Call{Caller: caller, Args: args}
+func NewSpread(expr IExpr, token *Token) *Spread {
+	return &Spread{Expr: expr, Token: token}
 }
 
 type Get struct {
@@ -223,6 +405,9 @@ func (g *Get) String() string {
 
 func (g *Get) GetValue() any {
 	temp := g.Object.GetValue()
+	if g.Name.Type == PRIVATE {
+		return GetPrivate(g.Name, temp)
+	}
 	if inst, ok := temp.(IInstance); ok {
 		return inst.Get(g.Name.Lexeme)
 	}
@@ -245,7 +430,7 @@ func NewThis() *This {
 }
 
 type Super struct {
-	Method *Token // 暂时从父类能拿的只能是方法，没有属性预定义
+	Method *Token // 方法 getter 或字段名
 }
 
 func (s *Super) String() string {
@@ -253,10 +438,96 @@ func (s *Super) String() string {
 }
 
 func (s *Super) GetValue() any {
-	obj := currEnv.Get("this").(*BaseInstance)
-	return obj.GetSuperMethod(s.Method.Lexeme)
+	// super 绑定到方法定义所在的类或 trait 而不是实例的类
+	var owner *MethodTable
+	var parent *BaseClass
+	switch temp := currEnv.GetOrNil(OWNER_KEY).(type) {
+	case *BaseClass:
+		owner = &temp.MethodTable
+		parent = temp.Parent
+	case *BaseTrait:
+		owner = &temp.MethodTable
+	default:
+		RuntimeError(s.Method, "super used outside a subclass method")
+	}
+	switch this := currEnv.Get("this").(type) {
+	case *BaseInstance:
+		return this.GetSuper(s.Method, owner, parent)
+	case *BaseClass:
+		return this.GetSuper(s.Method, parent)
+	}
+	RuntimeError(s.Method, "super used outside a subclass method")
+	return nil
 }
 
 func NewSuper(method *Token) *Super {
 	return &Super{Method: method}
 }
+
+type ListLiteral struct { // [ expr, expr ]
+	Items []IExpr
+}
+
+func (l *ListLiteral) String() string {
+	items := make([]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		items = append(items, item.String())
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func (l *ListLiteral) GetValue() any { // 每次求值都创建新的列表
+	return NewList(EvalArgs(l.Items))
+}
+
+func NewListLiteral(items []IExpr) *ListLiteral {
+	return &ListLiteral{Items: items}
+}
+
+type MapLiteral struct { // { key: val, key: val }
+	Token      *Token // { 用于报错定位
+	Keys, Vals []IExpr
+}
+
+func (m *MapLiteral) String() string {
+	items := make([]string, 0, len(m.Keys))
+	for i := 0; i < len(m.Keys); i++ {
+		items = append(items, m.Keys[i].String()+": "+m.Vals[i].String())
+	}
+	return "{" + strings.Join(items, ", ") + "}"
+}
+
+func (m *MapLiteral) GetValue() any { // 每次求值都创建新的字典
+	res := NewMap()
+	for i := 0; i < len(m.Keys); i++ {
+		key := m.Keys[i].GetValue()
+		res.Put(m.Token, key, m.Vals[i].GetValue())
+	}
+	return res
+}
+
+func NewMapLiteral(token *Token, keys []IExpr, vals []IExpr) *MapLiteral {
+	return &MapLiteral{Token: token, Keys: keys, Vals: vals}
+}
+
+type Index struct { // Object[Index]
+	Object, Index IExpr
+	Token         *Token // [ 用于报错定位
+}
+
+func (i *Index) String() string {
+	return fmt.Sprintf("%s[%s]", i.Object, i.Index)
+}
+
+func (i *Index) GetValue() any {
+	temp := i.Object.GetValue()
+	if obj, ok := temp.(IIndex); ok {
+		return obj.GetIndex(i.Token, i.Index.GetValue())
+	}
+	RuntimeError(i.Token, fmt.Sprintf("%s can't be indexed", TypeName(temp)))
+	return nil
+}
+
+func NewIndex(object IExpr, index IExpr, token *Token) *Index {
+	return &Index{Object: object, Index: index, Token: token}
+}
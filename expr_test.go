@@ -0,0 +1,28 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestBinary(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "concat", Source: `print "count: " + 3; print 3 + "x"; print "a" + nil; print "b" + true;`,
+			Output: "count: 3\n3x\nanil\nbtrue\n"},
+		{Name: "type mismatch", Source: `print 1 - "a";`, Err: "[line 1] runtime error : operands of '-' must be numbers, got int and string"},
+		{Name: "add mismatch", Source: `print 1 + true;`, Err: "operands of '+' must be numbers or strings, got int and bool"},
+		{Name: "unary", Source: `print -"a";`, Err: "runtime error"},
+	})
+}
+
+func TestCondition(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "logical", Source: `print true and false; print false or true;`, Output: "false\ntrue\n"},
+		{Name: "short circuit", Source: `print false and 1; print true or 1;`, Output: "false\ntrue\n"},
+		{Name: "and left", Source: `print 1 and 2;`, Err: "[line 1] runtime error : operands of 'and' must be bool, got int"},
+		{Name: "or right", Source: "print false or\n\"s\";", Err: "[line 1] runtime error : operands of 'or' must be bool, got string"},
+		{Name: "if", Source: "var a = 1;\nif (a) { print a; }", Err: "[line 2] runtime error : if condition must be bool, got int"},
+		{Name: "for", Source: `for (var i = 0; nil; i = i + 1) { print i; }`, Err: "[line 1] runtime error : for condition must be bool, got nil"},
+	})
+}
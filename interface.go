@@ -0,0 +1,39 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "fmt"
+
+type BaseInterface struct { // 接口只包含方法签名 通过 implements 声明 类定义时检查是否全部实现
+	Name    string
+	Parents []*BaseInterface     // 继承的接口
+	Methods map[string]*Function // 方法签名 没有方法体
+}
+
+func NewBaseInterface(name string, parents []*BaseInterface, methods map[string]*Function) *BaseInterface {
+	return &BaseInterface{Name: name, Parents: parents, Methods: methods}
+}
+
+func (b *BaseInterface) CollectMethods(res map[string]*Requirement) { // 收集自身与父接口的方法签名
+	for _, parent := range b.Parents {
+		parent.CollectMethods(res)
+	}
+	for name, method := range b.Methods {
+		res[name] = NewRequirement(method, b.Name)
+	}
+}
+
+func (b *BaseInterface) String() string {
+	return fmt.Sprintf("<interface %s>", b.Name)
+}
+
+type Requirement struct { // 具体类必须实现的方法 来自抽象方法或接口
+	Decl  *Function
+	Owner string // 声明该方法的类或接口
+}
+
+func NewRequirement(decl *Function, owner string) *Requirement {
+	return &Requirement{Decl: decl, Owner: owner}
+}
@@ -0,0 +1,158 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "fmt"
+
+// for (var x in iterable) 使用的迭代协议
+// 本地集合实现 IIterable，用户类通过 iterator() 返回带有 next() 方法的对象，next() 返回 nil 表示迭代结束
+
+type IIterator interface {
+	Next() (any, bool) // 返回下一个元素 false 表示结束
+}
+
+type IIterable interface {
+	Iterator() IIterator
+}
+
+type IEntryIterable interface { // for (var k, v in obj) 每次迭代返回 [k, v]
+	EntryIterator() IIterator
+}
+
+type FuncIterator struct { // 使用闭包实现的迭代器
+	Func func() (any, bool)
+}
+
+func NewFuncIterator(func0 func() (any, bool)) *FuncIterator {
+	return &FuncIterator{Func: func0}
+}
+
+func (f *FuncIterator) Next() (any, bool) {
+	return f.Func()
+}
+
+func NewSliceIterator(items []any) *FuncIterator {
+	index := 0
+	return NewFuncIterator(func() (any, bool) {
+		if index >= len(items) {
+			return nil, false
+		}
+		index++
+		return items[index-1], true
+	})
+}
+
+type InstanceIterator struct { // 用户定义的迭代器 调用其 next 方法
+	Next0 ICall
+}
+
+func NewInstanceIterator(next ICall) *InstanceIterator {
+	return &InstanceIterator{Next0: next}
+}
+
+func (i *InstanceIterator) Next() (any, bool) {
+	res := i.Next0.Call(nil)
+	return res, res != nil
+}
+
+func GetIterator(val any) IIterator {
+	switch temp := val.(type) {
+	case IIterable:
+		return temp.Iterator()
+	case string: // 按字符迭代
+		items := make([]any, 0)
+		for _, ch := range temp {
+			items = append(items, string(ch))
+		}
+		return NewSliceIterator(items)
+	case *BaseInstance:
+		if method := temp.Class.FindMethod("iterator"); method != nil { // 可迭代对象 iterator() 可以返回迭代器对象或本地集合
+			if res := method.BindThis(temp).Call(nil); res != any(temp) {
+				return GetIterator(res)
+			}
+		}
+		if method := temp.Class.FindMethod("next"); method != nil { // 对象本身就是迭代器
+			return NewInstanceIterator(method.BindThis(temp))
+		}
+	}
+	panic(fmt.Sprintf("%s not iterable", TypeName(val)))
+}
+
+func GetEntryIterator(val any) IIterator {
+	if temp, ok := val.(IEntryIterable); ok {
+		return temp.EntryIterator()
+	}
+	return GetIterator(val) // 其余类型的元素本身需要是 [k, v]
+}
+
+func (l *List) Iterator() IIterator { // 迭代过程中可以修改列表
+	index := 0
+	return NewFuncIterator(func() (any, bool) {
+		if index >= len(l.Items) {
+			return nil, false
+		}
+		index++
+		return l.Items[index-1], true
+	})
+}
+
+func (l *List) EntryIterator() IIterator { // [下标, 元素]
+	index := 0
+	return NewFuncIterator(func() (any, bool) {
+		if index >= len(l.Items) {
+			return nil, false
+		}
+		index++
+		return NewList([]any{int64(index - 1), l.Items[index-1]}), true
+	})
+}
+
+func (m *Map) Iterator() IIterator { // 迭代键 迭代开始时的快照
+	items := make([]any, 0, len(m.Entries))
+	for _, entry := range m.Entries {
+		items = append(items, entry.Key)
+	}
+	return NewSliceIterator(items)
+}
+
+func (m *Map) EntryIterator() IIterator { // [键, 值]
+	items := make([]any, 0, len(m.Entries))
+	for _, entry := range m.Entries {
+		items = append(items, NewList([]any{entry.Key, entry.Val}))
+	}
+	return NewSliceIterator(items)
+}
+
+func (h *HashSet) Iterator() IIterator {
+	return NewSliceIterator(h.Values())
+}
+
+type Range struct { // start..end 左闭右开 迭代时不创建列表
+	Start, End int64
+}
+
+func NewRange(start int64, end int64) *Range {
+	return &Range{Start: start, End: end}
+}
+
+func (r *Range) Iterator() IIterator {
+	curr := r.Start
+	return NewFuncIterator(func() (any, bool) {
+		if curr >= r.End {
+			return nil, false
+		}
+		curr++
+		return curr - 1, true
+	})
+}
+
+func (r *Range) Contains(token *Token, val any) bool {
+	temp, ok := val.(int64)
+	return ok && temp >= r.Start && temp < r.End
+}
+
+func (r *Range) String() string {
+	return fmt.Sprintf("%d..%d", r.Start, r.End)
+}
@@ -0,0 +1,21 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestForIn(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "user iterator", Source: `class It { init(n) { this.i = 0; this.n = n; } iterator() { return this; }
+  next() { var res = nil; if (this.i < this.n) { this.i = this.i + 1; res = this.i; } return res; } }
+for (var x in It(3)) { print x; }`, Output: "1\n2\n3\n"},
+		{Name: "iterator returns list", Source: `class Bag { iterator() { return [1, 2]; } } for (var x in Bag()) { print x; }`, Output: "1\n2\n"},
+		{Name: "range", Source: `for (var i in 0..3) { print i; }`, Output: "0\n1\n2\n"},
+		{Name: "string", Source: `for (var c in "中a") { print c; }`, Output: "中\na\n"},
+		{Name: "index and item", Source: `for (var i, x in ["a", "b"]) { print i; print x; }`, Output: "0\na\n1\nb\n"},
+		{Name: "set", Source: `for (var x in set(1, "a")) { print x; }`, Output: "1\na\n"},
+		{Name: "not iterable", Source: "for (var x in 1) {}", Err: "int not iterable"},
+	})
+}
@@ -0,0 +1,132 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type List struct { // 本地列表类型 [1, 2, 3]
+	Items []any
+}
+
+func NewList(items []any) *List {
+	return &List{Items: items}
+}
+
+func (l *List) Get(name string) any { // 列表只有方法 没有字段
+	switch name {
+	case "push":
+		return NewNativeFunc("push", 1, func(args []any) any {
+			l.Items = append(l.Items, args[0])
+			return nil
+		})
+	case "pop":
+		return NewNativeFunc("pop", 0, func(args []any) any {
+			if len(l.Items) == 0 {
+				RuntimeError(callToken, "pop from empty list")
+			}
+			res := l.Items[len(l.Items)-1]
+			l.Items = l.Items[:len(l.Items)-1]
+			return res
+		})
+	case "insert": // 可以插入到末尾 所以下标范围是 [-len, len]
+		return NewNativeFunc("insert", 2, func(args []any) any {
+			index := NormIndex(callToken, args[0], len(l.Items)+1)
+			l.Items = append(l.Items, nil)
+			copy(l.Items[index+1:], l.Items[index:])
+			l.Items[index] = args[1]
+			return nil
+		})
+	case "remove": // 按下标移除并返回移除的元素
+		return NewNativeFunc("remove", 1, func(args []any) any {
+			index := NormIndex(callToken, args[0], len(l.Items))
+			res := l.Items[index]
+			l.Items = append(l.Items[:index], l.Items[index+1:]...)
+			return res
+		})
+	case "len":
+		return NewNativeFunc("len", 0, func(args []any) any {
+			return int64(len(l.Items))
+		})
+	case "contains":
+		return NewNativeFunc("contains", 1, func(args []any) any {
+			return l.Contains(callToken, args[0])
+		})
+	case "slice": // 左闭右开 越界部分会被截断
+		return NewNativeFunc("slice", 2, func(args []any) any {
+			start, end := ClampIndex(callToken, args[0], len(l.Items)), ClampIndex(callToken, args[1], len(l.Items))
+			items := make([]any, 0)
+			if start < end {
+				items = append(items, l.Items[start:end]...)
+			}
+			return NewList(items)
+		})
+	default:
+		panic(fmt.Sprintf("list no method name %s", name))
+	}
+}
+
+func (l *List) Set(name *Token, val any) {
+	RuntimeError(name, fmt.Sprintf("can't set field %s on list", name.Lexeme))
+}
+
+func (l *List) GetIndex(token *Token, index any) any {
+	return l.Items[NormIndex(token, index, len(l.Items))]
+}
+
+func (l *List) SetIndex(token *Token, index any, val any) {
+	l.Items[NormIndex(token, index, len(l.Items))] = val
+}
+
+func (l *List) Contains(token *Token, val any) bool {
+	for _, item := range l.Items {
+		if Equal(item, val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *List) String() string {
+	items := make([]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		items = append(items, ReprString(item))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func NormIndex(token *Token, index any, size int) int { // 负数下标从末尾开始计算 越界报错
+	temp, ok := index.(int64)
+	if !ok {
+		RuntimeError(token, fmt.Sprintf("list index must be int, got %s", TypeName(index)))
+	}
+	res := temp
+	if res < 0 {
+		res += int64(size)
+	}
+	if res < 0 || res >= int64(size) {
+		RuntimeError(token, fmt.Sprintf("list index %d out of range for length %d", temp, size))
+	}
+	return int(res)
+}
+
+func ClampIndex(token *Token, index any, size int) int { // 负数下标从末尾开始计算 越界截断到 [0, size]
+	temp, ok := index.(int64)
+	if !ok {
+		RuntimeError(token, fmt.Sprintf("list index must be int, got %s", TypeName(index)))
+	}
+	if temp < 0 {
+		temp += int64(size)
+	}
+	if temp < 0 {
+		return 0
+	}
+	if temp > int64(size) {
+		return size
+	}
+	return int(temp)
+}
@@ -0,0 +1,53 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestList(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "literal", Source: `var xs = [1, "a", nil]; print xs; print xs[1]; print xs[-1];`, Output: "[1, \"a\", nil]\na\nnil\n"},
+		{Name: "set index", Source: `var xs = [1, 2]; xs[0] = 5; xs[-1] = 6; print xs;`, Output: "[5, 6]\n"},
+		{Name: "methods", Source: `var xs = [1, 2, 3];
+xs.push(4); print xs.pop(); xs.insert(0, 0); print xs.remove(-1); print xs; print xs.len();
+print xs.contains(2); print xs.slice(1, 10); print xs.slice(-2, 3);`,
+			Output: "4\n3\n[0, 1, 2]\n3\ntrue\n[1, 2]\n[1, 2]\n"},
+		{Name: "out of range", Source: "var xs = [1, 2];\nprint xs[5];", Err: "[line 2] runtime error : list index 5 out of range for length 2"},
+		{Name: "set out of range", Source: "var xs = [];\nxs[-1] = 1;", Err: "[line 2] runtime error : list index -1 out of range for length 0"},
+		{Name: "index type", Source: `print [1][true];`, Err: "[line 1] runtime error : list index must be int, got bool"},
+		{Name: "pop empty", Source: "var xs = [];\n\nxs.pop();", Err: "[line 3] runtime error : pop from empty list"},
+		{Name: "remove", Source: "var xs = [1];\nxs.remove(1);", Err: "[line 2] runtime error : list index 1 out of range for length 1"},
+		{Name: "insert", Source: "var xs = [1];\nxs.insert(3, 0);", Err: "[line 2] runtime error : list index 3 out of range for length 2"},
+		{Name: "nested call", Source: "func f(xs) {\n  return xs.pop();\n}\nprint f([1]);\nf([]);", Output: "1\n", Err: "[line 2] runtime error : pop from empty list"},
+		{Name: "not indexable", Source: "var a = 1;\nprint a[0];", Err: "[line 2] runtime error : int can't be indexed"},
+	})
+}
+
+func TestMap(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "literal", Source: `var m = {"a": 1, 2: "b"}; m["c"] = 3; print m; print m["a"]; print m.keys(); print m.values();`,
+			Output: "{\"a\": 1, 2: \"b\", \"c\": 3}\n1\n[\"a\", 2, \"c\"]\n[1, \"b\", 3]\n"},
+		{Name: "methods", Source: `var m = {"a": 1}; print m.has("a"); print m.delete("a"); print m.delete("a"); print m.len();`,
+			Output: "true\ntrue\nfalse\n0\n"},
+		{Name: "missing key", Source: "var m = {};\nprint m[\"x\"];", Err: "[line 2] runtime error : key x not found in map"},
+		{Name: "object key", Source: `class A {} var a = A(); var m = {a: 1}; print m[a]; print A() in m;`, Output: "1\nfalse\n"},
+		{Name: "unhashable key", Source: "var m = {[1]: 2};", Err: "[line 1] runtime error : list can't be used as map key"},
+		{Name: "for in", Source: `for (var k, v in {"a": 1, "b": 2}) { print k + "=" + str(v); }`, Output: "a=1\nb=2\n"},
+	})
+}
+
+func TestSet(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "dedup", Source: `var s = set(1, 1.0, 2, "2"); print s; print 2 in s; print 3 in s;`, Output: "set(1, 2, \"2\")\ntrue\nfalse\n"},
+		{Name: "algebra", Source: `var a = set(1, 2, 3); var b = set(2, 3, 4); print a.union(b); print a.intersection(b); print a.difference(b); print b.difference(a).values();`,
+			Output: "set(1, 2, 3, 4)\nset(2, 3)\nset(1)\n[4]\n"},
+		{Name: "algebra needs set", Source: `set(1).union([1]);`, Err: "list not a set"},
+		{Name: "objects", Source: `class A {} var a = A(); var s = set(a, a, A()); print s.len(); print a in s;`, Output: "2\ntrue\n"},
+		{Name: "unhashable in", Source: "var s = set();\nprint [1] in s;", Err: "[line 2] runtime error : list can't be used as map key"},
+		{Name: "unhashable add", Source: "var s = set();\ns.add({});", Err: "[line 2] runtime error : map can't be used as map key"},
+		{Name: "custom eq", Source: "class P { __eq__(o) { return true; } }\nset(P());", Err: "[line 2] runtime error : P defines __eq__ and can't be used as map key"},
+		{Name: "in", Source: `print 1 in [1, 2]; print "b" in "abc"; print "k" in {"k": 1}; print 3 in 0..3;`, Output: "true\ntrue\ntrue\nfalse\n"},
+	})
+}
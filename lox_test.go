@@ -0,0 +1,55 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// 测试辅助 执行 lox 源码并收集 print 输出与错误信息
+
+type LoxCase struct {
+	Name   string
+	Source string
+	Output string // 期望的输出 每行一个值
+	Err    string // 期望错误信息包含的文本 为空表示不应出错
+}
+
+func RunLox(source string) (output string, err string) {
+	buff := &bytes.Buffer{}
+	oldOutput := Output
+	Output = buff
+	currEnv = NewEnvironment() // 每次使用新的全局作用域
+	callToken = nil
+	defer func() {
+		Output = oldOutput
+		if r := recover(); r != nil {
+			err = fmt.Sprint(r)
+		}
+		output = buff.String()
+	}()
+	runCode(source)
+	return
+}
+
+func RunLoxCases(t *testing.T, cases []*LoxCase) {
+	for _, item := range cases {
+		t.Run(item.Name, func(t *testing.T) {
+			output, err := RunLox(item.Source)
+			if item.Err == "" && err != "" {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if item.Err != "" && !strings.Contains(err, item.Err) {
+				t.Fatalf("error = %q, want contains %q", err, item.Err)
+			}
+			if output != item.Output {
+				t.Fatalf("output = %q, want %q", output, item.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type MapEntry struct {
+	Key, Val any
+}
+
+type Map struct { // 本地字典类型 {"a": 1} 按插入顺序迭代
+	Entries []*MapEntry
+	Indexes map[any]int // HashKey(key) -> Entries 下标
+}
+
+func NewMap() *Map {
+	return &Map{Entries: make([]*MapEntry, 0), Indexes: make(map[any]int)}
+}
+
+func (m *Map) Get(name string) any { // 字典只有方法 没有字段
+	switch name {
+	case "keys":
+		return NewNativeFunc("keys", 0, func(args []any) any {
+			items := make([]any, 0, len(m.Entries))
+			for _, entry := range m.Entries {
+				items = append(items, entry.Key)
+			}
+			return NewList(items)
+		})
+	case "values":
+		return NewNativeFunc("values", 0, func(args []any) any {
+			items := make([]any, 0, len(m.Entries))
+			for _, entry := range m.Entries {
+				items = append(items, entry.Val)
+			}
+			return NewList(items)
+		})
+	case "has":
+		return NewNativeFunc("has", 1, func(args []any) any {
+			return m.Contains(callToken, args[0])
+		})
+	case "delete": // 返回是否存在并删除
+		return NewNativeFunc("delete", 1, func(args []any) any {
+			return m.Delete(callToken, args[0])
+		})
+	case "len":
+		return NewNativeFunc("len", 0, func(args []any) any {
+			return int64(len(m.Entries))
+		})
+	default:
+		panic(fmt.Sprintf("map no method name %s", name))
+	}
+}
+
+func (m *Map) Set(name *Token, val any) {
+	RuntimeError(name, fmt.Sprintf("can't set field %s on map", name.Lexeme))
+}
+
+func (m *Map) GetIndex(token *Token, key any) any {
+	if index, ok := m.Indexes[HashKey(token, key)]; ok {
+		return m.Entries[index].Val
+	}
+	RuntimeError(token, fmt.Sprintf("key %s not found in map", ToString(key)))
+	return nil
+}
+
+func (m *Map) SetIndex(token *Token, key any, val any) {
+	m.Put(token, key, val)
+}
+
+func (m *Map) Put(token *Token, key any, val any) { // 存在覆盖，不存在创建 token 用于键无法哈希时报错
+	hash := HashKey(token, key)
+	if index, ok := m.Indexes[hash]; ok {
+		m.Entries[index].Val = val
+		return
+	}
+	m.Indexes[hash] = len(m.Entries)
+	m.Entries = append(m.Entries, &MapEntry{Key: key, Val: val})
+}
+
+func (m *Map) Contains(token *Token, key any) bool {
+	_, ok := m.Indexes[HashKey(token, key)]
+	return ok
+}
+
+func (m *Map) Delete(token *Token, key any) bool {
+	hash := HashKey(token, key)
+	index, ok := m.Indexes[hash]
+	if !ok {
+		return false
+	}
+	delete(m.Indexes, hash)
+	m.Entries = append(m.Entries[:index], m.Entries[index+1:]...)
+	for i := index; i < len(m.Entries); i++ { // 后面的元素下标前移
+		m.Indexes[HashKey(token, m.Entries[i].Key)] = i
+	}
+	return true
+}
+
+func (m *Map) String() string {
+	items := make([]string, 0, len(m.Entries))
+	for _, entry := range m.Entries {
+		items = append(items, ReprString(entry.Key)+": "+ReprString(entry.Val))
+	}
+	return "{" + strings.Join(items, ", ") + "}"
+}
+
+type NumKey string // 数字统一转换为精确的有理数文本 保证 1 1.0 1n 1m 是同一个键
+
+func HashKey(token *Token, val any) any { // 转换为可以作为 go map 键的值，== 相等的值必须得到相同的键
+	switch temp := val.(type) {
+	case nil, bool, string:
+		return val
+	case int64:
+		return NumKey(strconv.FormatInt(temp, 10))
+	case *big.Int:
+		return NumKey(temp.String())
+	case *Decimal:
+		return NumKey(temp.Val.RatString())
+	case float64:
+		if math.IsInf(temp, 0) || math.IsNaN(temp) {
+			return NumKey(strconv.FormatFloat(temp, 'g', -1, 64))
+		}
+		return NumKey(ToDec(temp).Val.RatString())
+	case *BaseInstance:
+		if temp.IsValue() {
+			return NewValueKey(token, temp)
+		}
+		if temp.Class.FindMethod("__eq__") == nil { // 普通实例按引用比较 使用指针作为键
+			return temp
+		}
+		// 自定义了 __eq__ 的实例无法保证相等的值得到相同的键
+		RuntimeError(token, fmt.Sprintf("%s defines __eq__ and can't be used as map key", TypeName(val)))
+	}
+	RuntimeError(token, fmt.Sprintf("%s can't be used as map key", TypeName(val)))
+	return nil
+}
@@ -0,0 +1,359 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// 数字运行时类型从低到高分为 int64 *big.Int float64 *Decimal 四级
+// 混合运算时提升为两者中较高的一级，其中 int64 *big.Int *Decimal 均为精确运算
+// float64 与 *Decimal 混合时 float64 按最短十进制表示转换为 *Decimal 保证精确小数不会被舍入
+
+const (
+	IntRank = iota
+	BigRank
+	FloatRank
+	DecRank
+)
+
+type Decimal struct { // 精确小数 基于有理数实现 保证始终可以用有限位小数表示
+	Val   *big.Rat
+	Scale int // 输出保留的小数位数 1.10m 输出为 1.10
+}
+
+func NewDecimal(val *big.Rat) *Decimal { // 使用最少的小数位数
+	return NewScaleDecimal(val, 0)
+}
+
+func NewScaleDecimal(val *big.Rat, scale int) *Decimal { // 小数位数不足以精确表示时自动扩大
+	if temp := DecScale(val); temp > scale {
+		scale = temp
+	}
+	return &Decimal{Val: val, Scale: scale}
+}
+
+func (d *Decimal) String() string {
+	return d.Val.FloatString(d.Scale)
+}
+
+func TextScale(text string) int { // 文本中小数点后的位数 科学计数法与分数不保留
+	index := strings.IndexByte(text, '.')
+	if index < 0 || strings.ContainsAny(text, "eE/") {
+		return 0
+	}
+	return len(text) - index - 1
+}
+
+func DecScale(val *big.Rat) int { // 有限小数的小数位数，分母只含 2 5 因子时才是有限小数，否则返回 -1
+	denom := new(big.Int).Set(val.Denom())
+	count2 := int(denom.TrailingZeroBits())
+	denom.Rsh(denom, uint(count2))
+	count5 := 0
+	five, div, mod := big.NewInt(5), new(big.Int), new(big.Int)
+	for div.QuoRem(denom, five, mod); mod.Sign() == 0; div.QuoRem(denom, five, mod) {
+		denom.Set(div)
+		count5++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return -1
+	}
+	if count2 > count5 {
+		return count2
+	}
+	return count5
+}
+
+func IsNumber(val any) bool {
+	switch val.(type) {
+	case int64, *big.Int, *Decimal, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func NumRank(val any) int {
+	switch val.(type) {
+	case int64:
+		return IntRank
+	case *big.Int:
+		return BigRank
+	case *Decimal:
+		return DecRank
+	case float64:
+		return FloatRank
+	default:
+		panic(fmt.Sprintf("%v not a number", val))
+	}
+}
+
+func ToBig(val any) *big.Int {
+	switch temp := val.(type) {
+	case int64:
+		return big.NewInt(temp)
+	case *big.Int:
+		return temp
+	default:
+		panic(fmt.Sprintf("%v can't convert to bigint", val))
+	}
+}
+
+func ToDec(val any) *Decimal {
+	switch temp := val.(type) {
+	case int64:
+		return NewDecimal(new(big.Rat).SetInt64(temp))
+	case *big.Int:
+		return NewDecimal(new(big.Rat).SetInt(temp))
+	case *Decimal:
+		return temp
+	case float64: // 按最短十进制表示转换 0.1 转换为 0.1 而不是其二进制近似值
+		res, ok := new(big.Rat).SetString(strconv.FormatFloat(temp, 'f', -1, 64))
+		if !ok {
+			panic(fmt.Sprintf("%v can't convert to decimal", val))
+		}
+		return NewDecimal(res)
+	default:
+		panic(fmt.Sprintf("%v can't convert to decimal", val))
+	}
+}
+
+func ToFloat(val any) float64 {
+	switch temp := val.(type) {
+	case int64:
+		return float64(temp)
+	case *big.Int:
+		res, _ := new(big.Float).SetInt(temp).Float64()
+		return res
+	case *Decimal:
+		res, _ := temp.Val.Float64()
+		return res
+	case float64:
+		return temp
+	default:
+		panic(fmt.Sprintf("%v not a number", val))
+	}
+}
+
+func NumOp(operator *Token, left, right any) any { // 数字的四则运算与比较
+	rank := NumRank(left)
+	if temp := NumRank(right); temp > rank {
+		rank = temp
+	}
+	switch rank {
+	case IntRank:
+		return IntOp(operator, left.(int64), right.(int64))
+	case BigRank:
+		return BigOp(operator, ToBig(left), ToBig(right))
+	case FloatRank:
+		return FloatOp(operator, ToFloat(left), ToFloat(right))
+	default:
+		return DecOp(operator, ToDec(left), ToDec(right))
+	}
+}
+
+func IntOp(operator *Token, left, right int64) any {
+	switch operator.Type {
+	case GT:
+		return left > right
+	case GE:
+		return left >= right
+	case LT:
+		return left < right
+	case LE:
+		return left <= right
+	case ADD:
+		res := left + right
+		if (res > left) != (right > 0) { // 溢出检测
+			RuntimeError(operator, fmt.Sprintf("integer overflow %d + %d, use bigint", left, right))
+		}
+		return res
+	case SUB:
+		res := left - right
+		if (res < left) != (right > 0) {
+			RuntimeError(operator, fmt.Sprintf("integer overflow %d - %d, use bigint", left, right))
+		}
+		return res
+	case MUL:
+		if left == 0 || right == 0 {
+			return int64(0)
+		}
+		res := left * right
+		if res/right != left || (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64) {
+			RuntimeError(operator, fmt.Sprintf("integer overflow %d * %d, use bigint", left, right))
+		}
+		return res
+	case DIV: // 整数除法向零取整
+		if right == 0 {
+			RuntimeError(operator, fmt.Sprintf("integer division by zero %d / 0", left))
+		}
+		if left == math.MinInt64 && right == -1 {
+			RuntimeError(operator, fmt.Sprintf("integer overflow %d / %d, use bigint", left, right))
+		}
+		return left / right
+	default:
+		panic(fmt.Sprintf("invalid TokenType %v to int %d and %d", operator.Lexeme, left, right))
+	}
+}
+
+func BigOp(operator *Token, left, right *big.Int) any { // 运算结果总是新建，不修改字面量共享的值
+	switch operator.Type {
+	case GT, GE, LT, LE:
+		return CmpOp(operator, left.Cmp(right))
+	case ADD:
+		return new(big.Int).Add(left, right)
+	case SUB:
+		return new(big.Int).Sub(left, right)
+	case MUL:
+		return new(big.Int).Mul(left, right)
+	case DIV: // 与 int64 一致向零取整
+		if right.Sign() == 0 {
+			RuntimeError(operator, fmt.Sprintf("integer division by zero %v / 0", left))
+		}
+		return new(big.Int).Quo(left, right)
+	default:
+		panic(fmt.Sprintf("invalid TokenType %v to bigint %v and %v", operator.Lexeme, left, right))
+	}
+}
+
+func DecOp(operator *Token, left, right *Decimal) any { // 加减保留较多的小数位数 乘法位数相加
+	scale := left.Scale
+	if right.Scale > scale {
+		scale = right.Scale
+	}
+	switch operator.Type {
+	case GT, GE, LT, LE:
+		return CmpOp(operator, left.Val.Cmp(right.Val))
+	case ADD:
+		return NewScaleDecimal(new(big.Rat).Add(left.Val, right.Val), scale)
+	case SUB:
+		return NewScaleDecimal(new(big.Rat).Sub(left.Val, right.Val), scale)
+	case MUL:
+		return NewScaleDecimal(new(big.Rat).Mul(left.Val, right.Val), left.Scale+right.Scale)
+	case DIV: // 结果无法用有限小数表示时报错，而不是舍入
+		if right.Val.Sign() == 0 {
+			RuntimeError(operator, fmt.Sprintf("decimal division by zero %v / 0", left))
+		}
+		res := new(big.Rat).Quo(left.Val, right.Val)
+		if DecScale(res) < 0 {
+			RuntimeError(operator, fmt.Sprintf("non-terminating decimal %v / %v", left, right))
+		}
+		return NewScaleDecimal(res, scale)
+	default:
+		panic(fmt.Sprintf("invalid TokenType %v to decimal %v and %v", operator.Lexeme, left, right))
+	}
+}
+
+func FloatOp(operator *Token, left, right float64) any {
+	switch operator.Type {
+	case GT:
+		return left > right
+	case GE:
+		return left >= right
+	case LT:
+		return left < right
+	case LE:
+		return left <= right
+	case ADD:
+		return left + right
+	case SUB:
+		return left - right
+	case MUL:
+		return left * right
+	case DIV:
+		return left / right
+	default:
+		panic(fmt.Sprintf("invalid TokenType %v to float %v and %v", operator.Lexeme, left, right))
+	}
+}
+
+func CmpOp(operator *Token, cmp int) bool {
+	switch operator.Type {
+	case GT:
+		return cmp > 0
+	case GE:
+		return cmp >= 0
+	case LT:
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+func NumEqual(left, right any) bool { // 1 == 1.0
+	rank := NumRank(left)
+	if temp := NumRank(right); temp > rank {
+		rank = temp
+	}
+	switch rank {
+	case IntRank:
+		return left.(int64) == right.(int64)
+	case BigRank:
+		return ToBig(left).Cmp(ToBig(right)) == 0
+	case FloatRank:
+		return ToFloat(left) == ToFloat(right)
+	default:
+		return ToDec(left).Val.Cmp(ToDec(right).Val) == 0
+	}
+}
+
+func NumNeg(operator *Token, val any) any { // operator 为 - 用于报错定位
+	switch temp := val.(type) {
+	case int64:
+		if temp == math.MinInt64 {
+			RuntimeError(operator, fmt.Sprintf("integer overflow -(%d), use bigint", temp))
+		}
+		return -temp
+	case *big.Int:
+		return new(big.Int).Neg(temp)
+	case *Decimal:
+		return NewScaleDecimal(new(big.Rat).Neg(temp.Val), temp.Scale)
+	case float64:
+		return -temp
+	default:
+		panic(fmt.Sprintf("%v not a number", val))
+	}
+}
+
+func ParseBig(val any) *big.Int { // bigint(x) 支持整数 整数小数 文本
+	switch temp := val.(type) {
+	case int64, *big.Int:
+		return ToBig(temp)
+	case *Decimal:
+		if !temp.Val.IsInt() {
+			panic(fmt.Sprintf("%v not an integer", temp))
+		}
+		return new(big.Int).Set(temp.Val.Num())
+	case float64:
+		if temp != math.Trunc(temp) || math.IsInf(temp, 0) {
+			panic(fmt.Sprintf("%v not an integer", temp))
+		}
+		res, _ := new(big.Float).SetFloat64(temp).Int(nil)
+		return res
+	case string:
+		res, ok := new(big.Int).SetString(temp, 10)
+		if !ok {
+			panic(fmt.Sprintf("err bigint of %s", temp))
+		}
+		return res
+	default:
+		panic(fmt.Sprintf("%v can't convert to bigint", val))
+	}
+}
+
+func ParseDec(val any) *Decimal { // decimal(x) 支持数字 文本
+	if str, ok := val.(string); ok {
+		res, ok := new(big.Rat).SetString(str)
+		if !ok || DecScale(res) < 0 {
+			panic(fmt.Sprintf("err decimal of %s", str))
+		}
+		return NewScaleDecimal(res, TextScale(str))
+	}
+	return ToDec(val)
+}
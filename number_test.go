@@ -0,0 +1,30 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestNumber(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "int", Source: `print 7 / 2; print 1 + 2 * 3;`, Output: "3\n7\n"},
+		{Name: "float", Source: `print 1.5 + 1; print 2.0;`, Output: "2.5\n2\n"},
+		{Name: "overflow", Source: "var a = 9223372036854775807;\nprint a + 1;", Err: "[line 2] runtime error : integer overflow 9223372036854775807 + 1, use bigint"},
+		{Name: "neg overflow", Source: "var a = -9223372036854775807 - 1;\nprint -a;", Err: "[line 2] runtime error : integer overflow -(-9223372036854775808), use bigint"},
+		{Name: "div by zero", Source: "print 1 /\n0;", Err: "[line 1] runtime error : integer division by zero 1 / 0"},
+		{Name: "bigint div by zero", Source: "print 1n / 0;", Err: "[line 1] runtime error : integer division by zero 1 / 0"},
+		{Name: "decimal div by zero", Source: "print 1.5m / 0;", Err: "[line 1] runtime error : decimal division by zero 1.5 / 0"},
+		{Name: "bigint", Source: `print 9223372036854775807n + 1; print bigint("123") * 2;`,
+			Output: "9223372036854775808\n246\n"},
+		{Name: "decimal", Source: `print 0.1m + 0.2m; print 10m / 4;`, Output: "0.3\n2.5\n"},
+		{Name: "decimal scale", Source: `print 1.10m; print 1.10m + 1; print 1.5m * 1.10m; print -1.10m; print decimal("2.50");`,
+			Output: "1.10\n2.10\n1.650\n-1.10\n2.50\n"},
+		{Name: "decimal with float", Source: `print 0.1 + 0.2m; print 1.5m + 0.1; print 0.1 == 0.1m; print 0.3m < 0.1 + 0.2;`,
+			Output: "0.3\n1.6\ntrue\ntrue\n"},
+		{Name: "decimal with float type", Source: `print type(0.1 + 0.2m);`, Output: "decimal\n"},
+		{Name: "non-terminating", Source: `print 1m / 3;`, Err: "[line 1] runtime error : non-terminating decimal 1 / 3"},
+		{Name: "mixed equal", Source: `print 1 == 1.0; print 1n == 1m; print 2 == 2.5;`, Output: "true\ntrue\nfalse\n"},
+		{Name: "map key", Source: `var m = {}; m[1] = "a"; m[1.0m] = "b"; print m;`, Output: "{1: \"b\"}\n"},
+	})
+}
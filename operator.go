@@ -0,0 +1,98 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "fmt"
+
+// 运算符重载 实例作为操作数时调用类中对应的特殊方法
+
+var (
+	OperatorMethods = map[TokenType]string{
+		ADD: "__add__",
+		SUB: "__sub__",
+		MUL: "__mul__",
+		DIV: "__div__",
+		EQ:  "__eq__",
+		NE:  "__ne__",
+		LT:  "__lt__",
+		LE:  "__le__",
+		GT:  "__gt__",
+		GE:  "__ge__",
+	}
+	ReflectedOperators = map[TokenType]TokenType{ // a < b 等价于 b > a
+		LT: GT,
+		LE: GE,
+		GT: LT,
+		GE: LE,
+		EQ: EQ,
+		NE: NE,
+	}
+)
+
+func CallOperator(obj any, name string, args ...any) (any, bool) { // 调用实例的运算符方法 不存在返回 false
+	inst, ok := obj.(*BaseInstance)
+	if !ok {
+		return nil, false
+	}
+	method := inst.Class.FindMethod(name)
+	if method == nil {
+		return nil, false
+	}
+	return method.BindThis(inst).Call(args), true
+}
+
+func MustBool(name string, val any) bool {
+	res, ok := val.(bool)
+	if !ok {
+		panic(fmt.Sprintf("%s must return bool, got %s", name, TypeName(val)))
+	}
+	return res
+}
+
+// InstanceBinary 至少一侧为实例时的二元运算，依次尝试 左侧方法 右侧的反向方法 由 < 与 == 推导
+func InstanceBinary(operator *Token, left, right any) any {
+	if res, ok := CallOperator(left, OperatorMethods[operator.Type], right); ok {
+		return res
+	}
+	if reflected, ok := ReflectedOperators[operator.Type]; ok {
+		if res, ok := CallOperator(right, OperatorMethods[reflected], left); ok {
+			return res
+		}
+	}
+	switch operator.Type {
+	case EQ, NE: // 没有定义 __eq__ 时比较引用
+		return Equal(left, right) == (operator.Type == EQ)
+	case GT, GE, LE: // 由 < 推导 a > b 等价于 !(a < b) && !(a == b)
+		if res, ok := CallOperator(left, "__lt__", right); ok {
+			less := MustBool("__lt__", res)
+			switch operator.Type {
+			case GT:
+				return !less && !Equal(left, right)
+			case GE:
+				return !less
+			default:
+				return less || Equal(left, right)
+			}
+		}
+	}
+	RuntimeError(operator, fmt.Sprintf("operator '%s' not supported for %s and %s, define %s",
+		operator.Lexeme, TypeName(left), TypeName(right), OperatorMethods[operator.Type]))
+	return nil
+}
+
+func (b *BaseInstance) GetIndex(token *Token, index any) any { // obj[index] 调用 __index__
+	if res, ok := CallOperator(b, "__index__", index); ok {
+		return res
+	}
+	RuntimeError(token, fmt.Sprintf("%s can't be indexed, define __index__", b.Class.Name))
+	return nil
+}
+
+func (b *BaseInstance) SetIndex(token *Token, index any, val any) { // obj[index] = val 调用 __setindex__
+	if _, ok := CallOperator(b, "__setindex__", index, val); ok {
+		return
+	}
+	RuntimeError(token, fmt.Sprintf("%s can't be indexed, define __setindex__", b.Class.Name))
+}
@@ -0,0 +1,31 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+const vector = `class V {
+  init(x) { this.x = x; }
+  __add__(o) { return V(this.x + o.x); }
+  __lt__(o) { return this.x < o.x; }
+  __eq__(o) { return this.x == o.x; }
+  __neg__() { return V(-this.x); }
+  toString() { return "V(" + str(this.x) + ")"; }
+}
+`
+
+func TestOperator(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "arithmetic", Source: vector + `print V(1) + V(2); print -V(3);`, Output: "V(3)\nV(-3)\n"},
+		{Name: "equal", Source: vector + `print V(1) == V(1); print V(1) != V(2);`, Output: "true\ntrue\n"},
+		{Name: "derived from lt", Source: vector + `print V(1) < V(2); print V(2) > V(1); print V(1) >= V(1); print V(2) <= V(1);`,
+			Output: "true\ntrue\ntrue\nfalse\n"},
+		{Name: "reflected", Source: `class N { init(x) { this.x = x; } __gt__(o) { return this.x > o; } } print 1 < N(5); print 9 < N(5);`, Output: "true\nfalse\n"},
+		{Name: "index and contains", Source: `class G { init() { this.d = {}; } __index__(k) { return this.d[k]; } __setindex__(k, v) { this.d[k] = v; } __contains__(k) { return this.d.has(k); } }
+var g = G(); g["a"] = 1; print g["a"]; print "a" in g; print "b" in g;`, Output: "1\ntrue\nfalse\n"},
+		{Name: "not supported", Source: "class P {}\nprint P() * 2;", Err: "[line 2] runtime error : operator '*' not supported for P and int, define __mul__"},
+		{Name: "not indexable", Source: "class P {}\nP()[0];", Err: "[line 2] runtime error : P can't be indexed, define __index__"},
+	})
+}
@@ -7,8 +7,12 @@ package main
 import "fmt"
 
 type Parser struct {
-	Tokens []*Token
-	Index  int
+	Tokens     []*Token
+	Index      int
+	ClassDepth int      // 当前所在类定义的层数 私有成员只能在类中访问
+	InInit     bool     // 当前函数为 init 方法 不能返回值
+	CanSuper   bool     // 当前在有父类或 trait 的类中 或在 trait 中
+	Supers     []string // 当前函数中 super. 之后的名称
 }
 
 func NewParser(tokens []*Token) *Parser {
@@ -26,9 +30,33 @@ func (p *Parser) Parse() []IStmt {
 	return res
 }
 
-func (p *Parser) Declaration() IStmt { // Declaration -> ClassDeclaration | FuncDeclaration | VarDeclaration | Statement | Assignment
+func (p *Parser) Declaration() IStmt { // Declaration -> strict? abstract? ClassDeclaration | TraitDeclaration | InterfaceDeclaration | FuncDeclaration | VarDeclaration | Statement | Assignment
+	if p.Get().Type == ID && p.Get().Lexeme == "strict" && (p.Peek(1).Type == CLASS || p.Peek(1).Type == ABSTRACT) {
+		p.Read() // strict 不是关键字 只作为类的修饰
+		abstract := p.Match(ABSTRACT)
+		p.MustMatch(CLASS)
+		res := p.ClassDeclaration(abstract).(*Class)
+		res.Strict = true
+		return res
+	}
+	if p.Match(ABSTRACT) {
+		p.MustMatch(CLASS)
+		return p.ClassDeclaration(true)
+	}
 	if p.Match(CLASS) {
-		return p.ClassDeclaration()
+		return p.ClassDeclaration(false)
+	}
+	if p.Match(TRAIT) {
+		return p.TraitDeclaration()
+	}
+	if p.Match(ENUM) {
+		return p.EnumDeclaration()
+	}
+	if p.Match(RECORD) {
+		return p.RecordDeclaration()
+	}
+	if p.Match(INTERFACE) {
+		return p.InterfaceDeclaration()
 	}
 	if p.Match(FUNC) {
 		return p.FuncDeclaration()
@@ -36,48 +64,286 @@ func (p *Parser) Declaration() IStmt { // Declaration -> ClassDeclaration | Func
 	if p.Match(VAR) {
 		return p.VarDeclaration()
 	}
-	if p.Get().Type == ID {
+	if p.Get().Type == ID || p.Get().Type == THIS || p.Get().Type == LEFT3 { // this.name = expr 也是赋值 [a, b] = expr 解构赋值
 		return p.Assignment()
 	}
 	return p.Statement()
 }
 
-func (p *Parser) ClassDeclaration() IStmt { // ClassDeclaration -> class ID ( < ID )? { FuncDeclaration* }
+func (p *Parser) ClassDeclaration(abstract bool) IStmt { // ClassDeclaration -> class ID ( < ID )? ( with ID ( , ID )* )? ( implements ID ( , ID )* )? { Member* }
 	name := p.MustRead(ID)
 	var parent *Token
 	if p.Match(LT) { // 可选父类
 		parent = p.MustRead(ID)
 	}
+	traits := make([]*Token, 0)
+	if p.Get().Type == ID && p.Get().Lexeme == "with" { // with 不是关键字 只在这里作为修饰
+		p.Read()
+		traits = append(traits, p.MustRead(ID))
+		for p.Match(COMMA) {
+			traits = append(traits, p.MustRead(ID))
+		}
+	}
+	interfaces := make([]*Token, 0)
+	if p.Get().Type == ID && p.Get().Lexeme == "implements" { // implements 同样不是关键字
+		p.Read()
+		interfaces = append(interfaces, p.MustRead(ID))
+		for p.Match(COMMA) {
+			interfaces = append(interfaces, p.MustRead(ID))
+		}
+	}
 	p.MustMatch(LEFT2)
 	methods := make([]*Function, 0)
+	staticMethods := make([]*Function, 0)
+	staticFields := make([]*Var, 0)
+	getters := make([]*Function, 0)
+	setters := make([]*Function, 0)
+	abstracts := make([]*Function, 0)
+	fields := make([]*Var, 0)
+	p.ClassDepth++
+	oldSuper := p.CanSuper
+	p.CanSuper = parent != nil || len(traits) > 0
+	// Member -> static? FieldDeclaration | static FuncDeclaration | abstract SignatureDeclaration | GetterDeclaration | SetterDeclaration | FuncDeclaration
 	for !p.Match(RIGHT2) {
-		methods = append(methods, p.FuncDeclaration())
+		if p.Match(VAR) {
+			fields = append(fields, p.FieldDeclaration())
+		} else if p.Match(ABSTRACT) {
+			method := p.SignatureDeclaration()
+			if !abstract {
+				panic(fmt.Sprintf("[line %d] abstract method %s in non-abstract class %s", method.Name.Line, method.Name.Lexeme, name.Lexeme))
+			}
+			abstracts = append(abstracts, method)
+		} else if p.Match(STATIC) {
+			if p.Match(VAR) {
+				staticFields = append(staticFields, p.FieldDeclaration())
+			} else {
+				staticMethods = append(staticMethods, p.FuncBody(p.MemberName(), false))
+			}
+		} else if p.Get().Type == ID && p.Peek(1).Type == LEFT2 {
+			getters = append(getters, p.GetterDeclaration())
+		} else if p.Get().Type == ID && p.Get().Lexeme == "set" && p.Peek(1).Type == ID && p.Peek(2).Type == LEFT {
+			setters = append(setters, p.SetterDeclaration())
+		} else {
+			methods = append(methods, p.MethodDeclaration(p.MemberName()))
+		}
 	}
-	return NewClass(name, parent, methods)
+	p.ClassDepth--
+	p.CanSuper = oldSuper
+	res := NewClass(name, parent, methods)
+	res.Traits = traits
+	res.StaticMethods = staticMethods
+	res.StaticFields = staticFields
+	res.Getters = getters
+	res.Setters = setters
+	res.Abstract = abstract
+	res.Abstracts = abstracts
+	res.Interfaces = interfaces
+	res.Fields = fields
+	return res
 }
 
-func (p *Parser) FuncDeclaration() *Function { // FuncDeclaration -> func ID( Param? )block
+func (p *Parser) TraitDeclaration() IStmt { // TraitDeclaration -> trait ID { FuncDeclaration* }
+	name := p.MustRead(ID)
+	p.MustMatch(LEFT2)
+	methods := make([]*Function, 0)
+	oldSuper := p.CanSuper
+	p.CanSuper = true // trait 中的 super 指向混入顺序中的下一个
+	for !p.Match(RIGHT2) {
+		methods = append(methods, p.MethodDeclaration(p.MustRead(ID)))
+	}
+	p.CanSuper = oldSuper
+	return NewTrait(name, methods)
+}
+
+func (p *Parser) EnumDeclaration() IStmt { // EnumDeclaration -> enum ID { Variant ( , Variant )* ,? ( ; FuncDeclaration* )? }
+	name := p.MustRead(ID)
+	p.MustMatch(LEFT2)
+	variants := make([]*EnumVariant, 0)
+	for p.Get().Type == ID { // Variant -> ID ( ( ID ( , ID )* )? )?
+		variant := p.Read()
+		var fields []*Token // 为 nil 表示没有字段的成员
+		if p.Match(LEFT) {
+			fields = make([]*Token, 0)
+			for !p.Match(RIGHT) {
+				fields = append(fields, p.MustRead(ID))
+				if !p.Match(COMMA) {
+					p.MustMatch(RIGHT)
+					break
+				}
+			}
+		}
+		variants = append(variants, NewEnumVariant(variant, fields))
+		if !p.Match(COMMA) {
+			break
+		}
+	}
+	methods := make([]*Function, 0)
+	p.ClassDepth++
+	if p.Match(SEMI) { // 成员之后可以定义方法 所有成员共享
+		for p.Get().Type != RIGHT2 {
+			methods = append(methods, p.MethodDeclaration(p.MemberName()))
+		}
+	}
+	p.ClassDepth--
+	p.MustMatch(RIGHT2)
+	return NewEnum(name, variants, methods)
+}
+
+func (p *Parser) RecordDeclaration() IStmt { // RecordDeclaration -> record ID ( ID ( , ID )* ) ( { FuncDeclaration* } | ; )
 	name := p.MustRead(ID)
 	p.MustMatch(LEFT)
-	params := make([]*Token, 0)
-	if !p.Match(RIGHT) { // Param -> ID ( , ID )*
-		params = append(params, p.MustRead(ID))
+	fields := make([]*Token, 0)
+	for !p.Match(RIGHT) {
+		fields = append(fields, p.MustRead(ID))
+		if !p.Match(COMMA) {
+			p.MustMatch(RIGHT)
+			break
+		}
+	}
+	methods := make([]*Function, 0)
+	if p.Match(SEMI) { // 没有方法
+		return NewRecord(name, fields, methods)
+	}
+	p.MustMatch(LEFT2)
+	p.ClassDepth++
+	for !p.Match(RIGHT2) {
+		methods = append(methods, p.MethodDeclaration(p.MemberName()))
+	}
+	p.ClassDepth--
+	return NewRecord(name, fields, methods)
+}
+
+func (p *Parser) InterfaceDeclaration() IStmt { // InterfaceDeclaration -> interface ID ( < ID ( , ID )* )? { SignatureDeclaration* }
+	name := p.MustRead(ID)
+	parents := make([]*Token, 0)
+	if p.Match(LT) {
+		parents = append(parents, p.MustRead(ID))
 		for p.Match(COMMA) {
-			params = append(params, p.MustRead(ID))
+			parents = append(parents, p.MustRead(ID))
 		}
-		p.MustMatch(RIGHT)
 	}
 	p.MustMatch(LEFT2)
-	body := p.Block()
-	return NewFunction(name, params, body)
+	methods := make([]*Function, 0)
+	for !p.Match(RIGHT2) {
+		methods = append(methods, p.SignatureDeclaration())
+	}
+	return NewInterface(name, parents, methods)
+}
+
+func (p *Parser) SignatureDeclaration() *Function { // SignatureDeclaration -> ID( Param? ) ; 只有签名没有方法体
+	name := p.MustRead(ID)
+	params, defaults, rest := p.Params()
+	p.MustMatch(SEMI)
+	return NewFunction(name, params, defaults, rest, nil)
+}
+
+func (p *Parser) GetterDeclaration() *Function { // GetterDeclaration -> ID block 读取属性时调用
+	name := p.MustRead(ID)
+	return p.FuncScope(false, func() *Function {
+		p.MustMatch(LEFT2)
+		body := p.Block()
+		return NewFunction(name, make([]*Token, 0), make([]IExpr, 0), nil, body)
+	})
+}
+
+func (p *Parser) SetterDeclaration() *Function { // SetterDeclaration -> set ID ( ID ) block 给属性赋值时调用
+	p.Read() // set 不是关键字 只在类中作为修饰
+	res := p.FuncDeclaration()
+	if len(res.Params) != 1 || res.Rest != nil {
+		panic(fmt.Sprintf("[line %d] setter %s must have exactly one param", res.Name.Line, res.Name.Lexeme))
+	}
+	return res
+}
+
+func (p *Parser) FieldDeclaration() *Var { // FieldDeclaration -> var MemberName ( = Expression )? ;
+	name := p.MemberName()
+	var expr IExpr
+	if p.Match(ASSIGN) {
+		expr = p.Expression()
+	}
+	p.MustMatch(SEMI)
+	return NewVar(name, expr)
+}
+
+func (p *Parser) MemberName() *Token { // MemberName -> ID | #ID 私有成员只能在类中出现
+	if p.Get().Type == PRIVATE {
+		name := p.Read()
+		if p.ClassDepth == 0 {
+			panic(fmt.Sprintf("[line %d] private member %s used outside class", name.Line, name.Lexeme))
+		}
+		return name
+	}
+	return p.MustRead(ID)
+}
+
+func (p *Parser) FuncDeclaration() *Function { // FuncDeclaration -> func ID( Param? )block
+	return p.FuncBody(p.MustRead(ID), false)
+}
+
+func (p *Parser) MethodDeclaration(name *Token) *Function { // 名称为 init 的方法是初始化方法
+	res := p.FuncBody(name, name.Lexeme == "init")
+	if name.Lexeme == "toString" && !NewBaseCall(res, nil).ArgsSize().Accept(0) { // 转换文本时不传参数 解析时保证可以无参调用
+		panic(fmt.Sprintf("[line %d] toString must take no params", name.Line))
+	}
+	return res
+}
+
+func (p *Parser) FuncBody(name *Token, init bool) *Function { // 函数名之后的部分 方法与函数共用
+	return p.FuncScope(init, func() *Function {
+		params, defaults, rest := p.Params()
+		p.MustMatch(LEFT2)
+		body := p.Block()
+		return NewFunction(name, params, defaults, rest, body)
+	})
+}
+
+func (p *Parser) FuncScope(init bool, parse func() *Function) *Function { // 解析函数体 进入时重置 退出时恢复函数相关的状态
+	oldInit := p.InInit // 嵌套函数中可以正常返回值
+	p.InInit = init
+	oldSupers := p.Supers
+	p.Supers = nil
+	res := parse()
+	res.Supers = p.Supers
+	p.InInit = oldInit
+	p.Supers = append(oldSupers, p.Supers...) // 嵌套函数中的 super 也属于外层方法
+	return res
+}
+
+func (p *Parser) Params() ([]*Token, []IExpr, *Token) { // 解析 ( Param? ) 返回参数 默认值 剩余参数
+	p.MustMatch(LEFT)
+	params := make([]*Token, 0)
+	defaults := make([]IExpr, 0)
+	var rest *Token
+	for !p.Match(RIGHT) { // Param -> ID ( = Expression )? ( , ID ( = Expression )? )* ( , ...ID )?  剩余参数只能在最后
+		if p.Match(DOT3) {
+			rest = p.MustRead(ID)
+			p.MustMatch(RIGHT)
+			break
+		}
+		param := p.MustRead(ID)
+		var default0 IExpr
+		if p.Match(ASSIGN) {
+			default0 = p.Expression()
+		} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil { // 有默认值的参数只能在最后
+			panic(fmt.Sprintf("[line %d] param %s without default follows default param", param.Line, param.Lexeme))
+		}
+		params = append(params, param)
+		defaults = append(defaults, default0)
+		if !p.Match(COMMA) {
+			p.MustMatch(RIGHT)
+			break
+		}
+	}
+	return params, defaults, rest
 }
 
 func (p *Parser) Assignment() IStmt { // Assignment -> ( call . )? leftExpr = Expression;
-	leftExpr := p.Expression() // 可能是  leftExpr  或  obj.leftExpr method().leftExpr  或单独的表达式
+	leftExpr := p.Expression() // 可能是  leftExpr  或  obj.leftExpr method().leftExpr  obj[index] 或单独的表达式
 	if p.Match(SEMI) {         // 只有左边单独的表达式
 		return NewExpression(leftExpr)
 	}
 	p.MustMatch(ASSIGN) // 赋值
+	assign := p.Tokens[p.Index-1]
 	rightExpr := p.Expression()
 	p.MustMatch(SEMI)
 	switch temp := leftExpr.(type) {
@@ -85,12 +351,34 @@ func (p *Parser) Assignment() IStmt { // Assignment -> ( call . )? leftExpr = Ex
 		return NewAssign(temp.Name, rightExpr)
 	case *Get: // 把解析到的 Get 转换为 Set
 		return NewSet(temp.Object, temp.Name, rightExpr)
+	case *Index: // 把解析到的 Index 转换为 SetIndex
+		return NewSetIndex(temp.Object, temp.Index, temp.Token, rightExpr)
+	case *ListLiteral: // [a, obj.b, ...c] = expr 解构赋值
+		targets := make([]IExpr, 0, len(temp.Items))
+		var rest IExpr
+		for i, item := range temp.Items {
+			if spread, ok := item.(*Spread); ok && i == len(temp.Items)-1 {
+				rest = spread.Expr
+				item = rest
+			} else {
+				targets = append(targets, item)
+			}
+			switch item.(type) {
+			case *Variable, *Get, *Index:
+			default:
+				panic(fmt.Sprintf("invalid unpack target %v", item))
+			}
+		}
+		return NewAssignUnpack(assign, targets, rest, rightExpr)
 	default:
 		panic(fmt.Sprintf("invalid left obj %v", leftExpr))
 	}
 }
 
-func (p *Parser) VarDeclaration() IStmt { // VarDeclaration -> var name ( = Expression) ? ;
+func (p *Parser) VarDeclaration() IStmt { // VarDeclaration -> var name ( = Expression) ? ; | var ( names ) = Expression ; | var [ names ] = Expression ;
+	if p.Get().Type == LEFT || p.Get().Type == LEFT3 {
+		return p.VarUnpackDeclaration()
+	}
 	name := p.MustRead(ID)
 	var expr IExpr
 	if p.Match(ASSIGN) {
@@ -100,6 +388,32 @@ func (p *Parser) VarDeclaration() IStmt { // VarDeclaration -> var name ( = Expr
 	return NewVar(name, expr)
 }
 
+func (p *Parser) VarUnpackDeclaration() IStmt { // names -> ID ( , ID )* ( , ...ID )? 剩余变量只能在最后
+	end := RIGHT
+	if p.Read().Type == LEFT3 {
+		end = RIGHT3
+	}
+	names := make([]*Token, 0)
+	var rest *Token
+	for !p.Match(end) {
+		if p.Match(DOT3) {
+			rest = p.MustRead(ID)
+			p.MustMatch(end)
+			break
+		}
+		names = append(names, p.MustRead(ID))
+		if !p.Match(COMMA) {
+			p.MustMatch(end)
+			break
+		}
+	}
+	p.MustMatch(ASSIGN)
+	token := p.Tokens[p.Index-1]
+	expr := p.Expression()
+	p.MustMatch(SEMI)
+	return NewVarUnpack(token, names, rest, expr)
+}
+
 func (p *Parser) Statement() IStmt { // Statement -> ReturnStatement | ForStatement | IfStatement | ExpressionStatement | PrintStatement | Block
 	if p.Match(RETURN) {
 		return p.ReturnStatement()
@@ -119,17 +433,31 @@ func (p *Parser) Statement() IStmt { // Statement -> ReturnStatement | ForStatem
 	return p.ExpressionStatement()
 }
 
-func (p *Parser) ReturnStatement() IStmt { // ReturnStatement -> return expression ;
+func (p *Parser) ReturnStatement() IStmt { // ReturnStatement -> return ( expression ( , expression )* )? ;
 	var res IExpr
 	if !p.Match(SEMI) {
+		if p.InInit {
+			panic(fmt.Sprintf("[line %d] can't return a value from an initializer", p.Get().Line))
+		}
 		res = p.Expression()
+		if p.Get().Type == COMMA { // 多个返回值打包为列表
+			items := []IExpr{res}
+			for p.Match(COMMA) {
+				items = append(items, p.Expression())
+			}
+			res = NewListLiteral(items)
+		}
 		p.MustMatch(SEMI)
 	}
 	return NewReturn(res)
 }
 
-func (p *Parser) ForStatement() IStmt { // For -> for (VarDeclaration?;Expression?;Assignment?){Statement?}
+func (p *Parser) ForStatement() IStmt { // For -> for (VarDeclaration?;Expression?;Assignment?){Statement?} | ForIn
+	token := p.Tokens[p.Index-1] // for
 	p.MustMatch(LEFT)
+	if p.Get().Type == VAR && (p.Peek(2).Type == IN || p.Peek(2).Type == COMMA) {
+		return p.ForInStatement()
+	}
 	var init IStmt
 	if !p.Match(SEMI) {
 		p.MustMatch(VAR)
@@ -150,10 +478,25 @@ func (p *Parser) ForStatement() IStmt { // For -> for (VarDeclaration?;Expressio
 	}
 	p.MustMatch(LEFT2)
 	body := p.Block() // 直接复用block 会再创建一个 变量作用域还好
-	return NewFor(init, condition, change, body)
+	return NewFor(token, init, condition, change, body)
+}
+
+func (p *Parser) ForInStatement() IStmt { // ForIn -> for (var ID ( , ID )? in Expression){Statement?}
+	p.MustMatch(VAR)
+	names := []*Token{p.MustRead(ID)}
+	if p.Match(COMMA) {
+		names = append(names, p.MustRead(ID))
+	}
+	p.MustMatch(IN)
+	iterable := p.Expression()
+	p.MustMatch(RIGHT)
+	p.MustMatch(LEFT2)
+	body := p.Block()
+	return NewForIn(names, iterable, body)
 }
 
 func (p *Parser) IfStatement() IStmt { // If -> if ( Expression ) { Statement } else { Statement }
+	token := p.Tokens[p.Index-1] // if
 	p.MustMatch(LEFT)
 	condition := p.Expression()
 	p.MustMatch(RIGHT)
@@ -164,7 +507,7 @@ func (p *Parser) IfStatement() IStmt { // If -> if ( Expression ) { Statement }
 		p.MustMatch(LEFT2)
 		elseBranch = p.Block()
 	}
-	return NewIf(condition, ifBranch, elseBranch)
+	return NewIf(token, condition, ifBranch, elseBranch)
 }
 
 func (p *Parser) Block() IStmt { // Block - > { Declaration* }
@@ -209,9 +552,19 @@ func (p *Parser) Equality() IExpr { // Equality -> Comparison (( != | == )Compar
 	return left
 }
 
-func (p *Parser) Comparison() IExpr { // Comparison -> Term (( > | >= | < | <= )Term)*
+func (p *Parser) Comparison() IExpr { // Comparison -> Range (( > | >= | < | <= | in | is )Range)*
+	left := p.Range()
+	for p.Get().Type == GT || p.Get().Type == GE || p.Get().Type == LT || p.Get().Type == LE || p.Get().Type == IN || p.Get().Type == IS {
+		operator := p.Read()
+		right := p.Range()
+		left = NewBinary(left, right, operator)
+	}
+	return left
+}
+
+func (p *Parser) Range() IExpr { // Range -> Term ( .. Term )?
 	left := p.Term()
-	for p.Get().Type == GT || p.Get().Type == GE || p.Get().Type == LT || p.Get().Type == LE {
+	if p.Get().Type == DOT2 {
 		operator := p.Read()
 		right := p.Term()
 		left = NewBinary(left, right, operator)
@@ -248,14 +601,19 @@ func (p *Parser) Unary() IExpr { // Unary -> (( ! | - )Unary)|Call
 	return p.Call()
 }
 
-func (p *Parser) Call() IExpr { // Call -> Primary ( ( args? ) | . ID )*    函数的多重调用 属性的多重调用
+func (p *Parser) Call() IExpr { // Call -> Primary ( ( args? ) | . ID | [ Expression ] )*    函数的多重调用 属性的多重调用 下标访问
 	expr := p.Primary() // call的对象主要是 id
 	for {
 		if p.Match(LEFT) {
 			expr = p.SingleCall(expr) // 递归函数的单次调用
 		} else if p.Match(DOT) {
-			name := p.MustRead(ID)
+			name := p.MemberName()
 			expr = NewGet(expr, name) // 属性多次点链接
+		} else if p.Get().Type == LEFT3 {
+			token := p.Read()
+			index := p.Expression()
+			p.MustMatch(RIGHT3)
+			expr = NewIndex(expr, index, token)
 		} else {
 			break
 		}
@@ -264,28 +622,78 @@ func (p *Parser) Call() IExpr { // Call -> Primary ( ( args? ) | . ID )*    函
 }
 
 func (p *Parser) SingleCall(expr IExpr) IExpr { // 单次调用
-	args := make([]IExpr, 0) // args -> ( Expression ( , Expression )* )
-	if !p.Match(RIGHT) {
-		args = append(args, p.Expression())
-		for p.Match(COMMA) {
-			args = append(args, p.Expression())
+	token := p.Tokens[p.Index-1] // (
+	args := make([]IExpr, 0)     // args -> ( Arg ( , Arg )* ( , ID : Expression )* )  具名参数只能在最后
+	named := make([]*NamedArg, 0)
+	for !p.Match(RIGHT) {
+		if p.Get().Type == ID && p.Peek(1).Type == COLON {
+			name := p.Read()
+			p.MustMatch(COLON)
+			named = append(named, NewNamedArg(name, p.Expression()))
+		} else if len(named) > 0 {
+			panic(fmt.Sprintf("[line %d] positional arg follows named arg", p.Get().Line))
+		} else {
+			args = append(args, p.Arg())
+		}
+		if !p.Match(COMMA) {
+			p.MustMatch(RIGHT)
+			break
 		}
-		p.MustMatch(RIGHT)
 	}
-	return NewCall(expr, args)
+	return NewCall(expr, args, named, token)
 }
 
-func (p *Parser) Primary() IExpr { // Primary -> NUM | STR | true | false | nil | '(' Expression ')' | id
+func (p *Parser) Arg() IExpr { // Arg -> ...? Expression  ...xs 展开可迭代对象
+	if p.Get().Type == DOT3 {
+		token := p.Read()
+		return NewSpread(p.Expression(), token)
+	}
+	return p.Expression()
+}
+
+func (p *Parser) Primary() IExpr { // Primary -> NUM | STR | TMPL | true | false | nil | '(' Expression ')' | id | '[' args? ']' | '{' entries? '}'
 	if p.Get().Type == FALSE || p.Get().Type == TRUE || p.Get().Type == NIL ||
 		p.Get().Type == NUM || p.Get().Type == STR {
 		return NewLiteral(p.Read())
 	}
+	if p.Get().Type == TMPL {
+		return p.Template()
+	}
+	if p.Match(LEFT3) { // 列表字面量 允许末尾多一个逗号
+		items := make([]IExpr, 0)
+		for !p.Match(RIGHT3) {
+			items = append(items, p.Arg())
+			if !p.Match(COMMA) {
+				p.MustMatch(RIGHT3)
+				break
+			}
+		}
+		return NewListLiteral(items)
+	}
+	if p.Match(LEFT2) { // 字典字面量 语句开头的 { 已经被解析为代码块，只有表达式中的 { 会走到这里
+		token := p.Tokens[p.Index-1]
+		keys, vals := make([]IExpr, 0), make([]IExpr, 0)
+		for !p.Match(RIGHT2) { // entries -> Expression : Expression ( , Expression : Expression )* ,?
+			keys = append(keys, p.Expression())
+			p.MustMatch(COLON)
+			vals = append(vals, p.Expression())
+			if !p.Match(COMMA) {
+				p.MustMatch(RIGHT2)
+				break
+			}
+		}
+		return NewMapLiteral(token, keys, vals)
+	}
 	if p.Match(THIS) { // 与id类似 不过取固定变量名 this
 		return NewThis()
 	}
 	if p.Match(SUPER) {
+		if !p.CanSuper {
+			panic(fmt.Sprintf("[line %d] super used outside a subclass method", p.Tokens[p.Index-1].Line))
+		}
 		p.MustMatch(DOT)
 		method := p.MustRead(ID)
+		p.Supers = append(p.Supers, method.Lexeme)
 		return NewSuper(method)
 	}
 	if p.Get().Type == ID {
@@ -297,6 +705,26 @@ func (p *Parser) Primary() IExpr { // Primary -> NUM | STR | true | false | nil
 	return NewGroup(expr)
 }
 
+func (p *Parser) Template() IExpr { // 插值字符串 文本部分作为字面量 插值部分单独解析为表达式
+	token := p.Read()
+	parts := make([]IExpr, 0)
+	for _, part := range token.Value.([]any) {
+		switch temp := part.(type) {
+		case string:
+			parts = append(parts, NewLiteral(NewToken(STR, temp, temp, token.Line)))
+		case []*Token:
+			parser := NewParser(temp)
+			parser.ClassDepth = p.ClassDepth
+			parser.CanSuper = p.CanSuper
+			parts = append(parts, parser.Expression())
+			parser.MustMatch(EOF) // 插值中只能有一个表达式
+			// 插值中的 super 属于外层方法
+			p.Supers = append(p.Supers, parser.Supers...)
+		}
+	}
+	return NewTemplate(parts)
+}
+
 func (p *Parser) MustMatch(type0 TokenType) {
 	if !p.Match(type0) {
 		panic(fmt.Sprintf("invalid token %v", p.Get()))
@@ -318,6 +746,13 @@ func (p *Parser) Get() *Token {
 	return p.Tokens[p.Index]
 }
 
+func (p *Parser) Peek(offset int) *Token { // 向后查看 不移动下标 超出时返回 EOF
+	if p.Index+offset >= len(p.Tokens) {
+		return p.Tokens[len(p.Tokens)-1]
+	}
+	return p.Tokens[p.Index+offset]
+}
+
 func (p *Parser) Read() *Token {
 	p.Index++
 	return p.Tokens[p.Index-1]
@@ -0,0 +1,82 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "fmt"
+
+// 私有成员 #name 只能在定义它的类的方法中通过 obj.#name 访问
+// 语法分析时拒绝类外的访问，运行时根据方法定义所在的类检查
+
+func CurrOwner() *BaseClass { // 当前执行代码所属的类 不在类中返回 nil
+	owner, _ := currEnv.GetOrNil(OWNER_KEY).(*BaseClass)
+	return owner
+}
+
+func CheckOwner(name *Token, owner *BaseClass, class *BaseClass) {
+	if owner == nil {
+		RuntimeError(name, fmt.Sprintf("private member %s accessed outside class", name.Lexeme))
+	}
+	if !class.IsSubclassOf(owner) {
+		RuntimeError(name, fmt.Sprintf("private member %s of %s not accessible from class %s", name.Lexeme, class.Name, owner.Name))
+	}
+}
+
+func GetPrivate(name *Token, obj any) any {
+	owner := CurrOwner()
+	switch temp := obj.(type) {
+	case *BaseInstance:
+		CheckOwner(name, owner, temp.Class)
+		return temp.GetPrivate(name, owner)
+	case *BaseClass: // 私有静态成员
+		CheckOwner(name, owner, temp)
+		return temp.Get(name.Lexeme)
+	}
+	panic(fmt.Sprintf("obj %v not a Instance", obj))
+}
+
+func SetPrivate(name *Token, obj any, val any) {
+	owner := CurrOwner()
+	switch temp := obj.(type) {
+	case *BaseInstance:
+		CheckOwner(name, owner, temp.Class)
+		temp.SetPrivate(name, owner, val)
+		return
+	case *BaseClass:
+		CheckOwner(name, owner, temp)
+		temp.Set(name, val)
+		return
+	}
+	panic(fmt.Sprintf("obj %v not a Instance", obj))
+}
+
+func (b *BaseInstance) Privates(owner *BaseClass) map[string]any { // 不存在时创建
+	res, ok := b.Owners[owner]
+	if !ok {
+		res = make(map[string]any)
+		b.Owners[owner] = res
+	}
+	return res
+}
+
+func (b *BaseInstance) GetPrivate(name *Token, owner *BaseClass) any { // 私有方法只在所属类中查找 不会被子类覆盖
+	if val, ok := b.Owners[owner][name.Lexeme]; ok {
+		return val
+	}
+	if method, ok := owner.Methods[name.Lexeme]; ok {
+		return method.BindThis(b)
+	}
+	RuntimeError(name, fmt.Sprintf("class %s no private member %s", owner.Name, name.Lexeme))
+	return nil
+}
+
+func (b *BaseInstance) SetPrivate(name *Token, owner *BaseClass, val any) { // 写入当前类的私有字段
+	if b.IsValue() { // 与 Set 相同 值类型不可修改
+		RuntimeError(name, fmt.Sprintf("can't set field %s of %s, use with to copy", name.Lexeme, b.Class.Name))
+	}
+	if b.Class.IsStrict() && !owner.DeclaresField(name.Lexeme) {
+		RuntimeError(name, fmt.Sprintf("strict class %s has no field %s, declare it with var", owner.Name, name.Lexeme))
+	}
+	b.Privates(owner)[name.Lexeme] = val
+}
@@ -0,0 +1,46 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+const account = `class Account {
+  var #balance = 0;
+  init(n) { this.#balance = n; }
+  deposit(x) { this.#check(x); this.#balance = this.#balance + x; }
+  #check(x) { if (x < 0) { print "negative"; } }
+  get() { return this.#balance; }
+}
+`
+
+func TestPrivate(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "inside class", Source: account + `var a = Account(10); a.deposit(5); a.deposit(-1); print a.get();`,
+			Output: "negative\n14\n"},
+		{Name: "outside class", Source: account + `print Account(1).#balance;`, Err: "private member #balance used outside class"},
+		{Name: "other class read", Source: account + "class X { peek(p) { return p.#balance; } }\nprint X().peek(Account(1));",
+			Err: "[line 8] runtime error : private member #balance of Account not accessible from class X"},
+		{Name: "other class write", Source: account + "class X { hack(p) { p.#balance = 99; } }\nvar a = Account(1); X().hack(a);",
+			Err: "not accessible from class X"},
+		{Name: "other class destructuring write", Source: account + "class X { hack(p) { [p.#balance] = [99]; } }\nvar a = Account(1); X().hack(a);",
+			Err: "not accessible from class X"},
+		{Name: "destructuring inside class", Source: `class P { init() { [this.#a, this.#b] = [1, 2]; } sum() { return this.#a + this.#b; } } print P().sum();`,
+			Output: "3\n"},
+		{Name: "subclass", Source: account + `class Sub < Account { peek() { return this.#balance; } } Sub(1).peek();`,
+			Err: "class Sub no private member #balance"},
+		{Name: "subclass own field", Source: account + `class Sub < Account { init(n) { super.init(1); this.#balance = n; } own() { return this.#balance; } } var s = Sub(7); print s.own(); print s.get();`,
+			Output: "7\n1\n"},
+		{Name: "subclass same name declared", Source: `class A { var #x = 1; getX() { return this.#x; } } class B < A { var #x = 2; getB() { return this.#x; } } var b = B(); print b.getX(); print b.getB();`,
+			Output: "1\n2\n"},
+		{Name: "record immutable", Source: "record R(n) { reset() { this.#n = 1; } }\nR(0).reset();", Err: "[line 1] runtime error : can't set field #n of R, use with to copy"},
+		{Name: "private method from outside", Source: account + "class X { call(p) { return p.#check(1); } }\nX().call(Account(1));",
+			Err: "not accessible from class X"},
+		{Name: "in hides private", Source: account + `var a = Account(1); print "#balance" in a; a.pub = 1; print "pub" in a;`,
+			Output: "false\ntrue\n"},
+		{Name: "reflection hides private", Source: account + `var a = Account(1); print fields(a); print methods(Account);`,
+			Output: "[]\n[\"deposit\", \"get\", \"init\"]\n"},
+		{Name: "getField private", Source: account + `getField(Account(1), "#balance");`, Err: "getField can't access private member #balance"},
+	})
+}
@@ -0,0 +1,154 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 运行时类型检查与反射 x is Class 与 type className methods fields 等本地方法
+// 私有成员 #name 不能通过反射访问
+
+func InjectReflectFunc(env *Environment) {
+	env.Define("type", NewNativeFunc("type", 1, func(args []any) any { // 实例返回类 其余返回类型名
+		if inst, ok := args[0].(*BaseInstance); ok {
+			return inst.Class
+		}
+		return TypeName(args[0])
+	}))
+	env.Define("className", NewNativeFunc("className", 1, func(args []any) any {
+		return ClassOf("className", args[0]).Name
+	}))
+	env.Define("superclass", NewNativeFunc("superclass", 1, func(args []any) any { // 没有父类返回 nil
+		if parent := ClassOf("superclass", args[0]).Parent; parent != nil {
+			return parent
+		}
+		return nil
+	}))
+	env.Define("methods", NewNativeFunc("methods", 1, func(args []any) any { // 包含继承与混入的方法
+		names := make([]string, 0)
+		for _, table := range ClassOf("methods", args[0]).Linear {
+			for name := range table.Methods {
+				names = append(names, name)
+			}
+		}
+		return PublicNames(names)
+	}))
+	env.Define("fields", NewNativeFunc("fields", 1, func(args []any) any {
+		inst := InstanceOf("fields", args[0])
+		names := make([]string, 0, len(inst.Fields))
+		for name := range inst.Fields {
+			names = append(names, name)
+		}
+		return PublicNames(names)
+	}))
+	env.Define("hasField", NewNativeFunc("hasField", 2, func(args []any) any {
+		_, ok := InstanceOf("hasField", args[0]).Fields[FieldName("hasField", args[1])]
+		return ok
+	}))
+	env.Define("getField", NewNativeFunc("getField", 2, func(args []any) any {
+		inst := InstanceOf("getField", args[0])
+		name := FieldName("getField", args[1])
+		if val, ok := inst.Fields[name]; ok {
+			return val
+		}
+		panic(fmt.Sprintf("%s has no field %s", inst.Class.Name, name))
+	}))
+	env.Define("setField", NewNativeFunc("setField", 3, func(args []any) any { // 与 obj.name = val 相同 会调用 setter
+		name := NewToken(ID, FieldName("setField", args[1]), nil, callToken.Line) // 报错定位到调用处
+		InstanceOf("setField", args[0]).Set(name, args[2])
+		return nil
+	}))
+}
+
+func ClassOf(func0 string, val any) *BaseClass { // 实例取其类
+	switch temp := val.(type) {
+	case *BaseClass:
+		return temp
+	case *BaseInstance:
+		return temp.Class
+	}
+	panic(fmt.Sprintf("%s need class or instance, got %s", func0, TypeName(val)))
+}
+
+func InstanceOf(func0 string, val any) *BaseInstance {
+	if inst, ok := val.(*BaseInstance); ok {
+		return inst
+	}
+	panic(fmt.Sprintf("%s need instance, got %s", func0, TypeName(val)))
+}
+
+func FieldName(func0 string, val any) string {
+	name, ok := val.(string)
+	if !ok {
+		panic(fmt.Sprintf("%s need string name, got %s", func0, TypeName(val)))
+	}
+	if strings.HasPrefix(name, "#") {
+		panic(fmt.Sprintf("%s can't access private member %s", func0, name))
+	}
+	return name
+}
+
+func PublicNames(names []string) *List { // 去重排序并去掉私有成员
+	sort.Strings(names)
+	items := make([]any, 0, len(names))
+	for i, name := range names {
+		if strings.HasPrefix(name, "#") || (i > 0 && names[i-1] == name) {
+			continue
+		}
+		items = append(items, name)
+	}
+	return NewList(items)
+}
+
+func Is(operator *Token, left, right any) bool { // 右侧为类 trait 接口或类型名
+	inst, ok := left.(*BaseInstance)
+	switch temp := right.(type) {
+	case string:
+		return TypeName(left) == temp
+	case *BaseClass:
+		return ok && inst.Class.IsSubclassOf(temp)
+	case *BaseTrait:
+		return ok && inst.Class.HasTrait(temp)
+	case *BaseInterface:
+		return ok && inst.Class.Implements(temp)
+	}
+	RuntimeError(operator, fmt.Sprintf("right of 'is' must be class, trait, interface or type name, got %s", TypeName(right)))
+	return false
+}
+
+func (b *BaseClass) HasTrait(trait *BaseTrait) bool {
+	for _, table := range b.Linear {
+		if table == &trait.MethodTable {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BaseClass) Implements(inter *BaseInterface) bool { // 父类声明的接口与接口的父接口都算
+	for class := b; class != nil; class = class.Parent {
+		for _, item := range class.Interfaces {
+			if item.Extends(inter) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (b *BaseInterface) Extends(other *BaseInterface) bool { // 包含自身
+	if b == other {
+		return true
+	}
+	for _, parent := range b.Parents {
+		if parent.Extends(other) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestIs(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "class", Source: `class Shape {} class Circle < Shape {} var c = Circle(); print c is Shape; print c is Circle; print Shape() is Circle; print 1 is Shape;`,
+			Output: "true\ntrue\nfalse\nfalse\n"},
+		{Name: "type name", Source: `print 1 is "int"; print "a" is "string"; print nil is "int";`, Output: "true\ntrue\nfalse\n"},
+		{Name: "invalid right", Source: "print 1 is\n2;", Err: "runtime error : right of 'is' must be class, trait, interface or type name, got int"},
+	})
+}
+
+func TestReflect(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "type", Source: `class C {} print type(1); print type("s"); print type(nil); print type(C()) == C; print type([]); print type(true);`,
+			Output: "int\nstring\nnil\ntrue\nlist\nbool\n"},
+		{Name: "class name", Source: `class A {} class B < A {} print className(B()); print className(B); print superclass(B); print superclass(A);`,
+			Output: "B\nB\n<class A>\nnil\n"},
+		{Name: "members", Source: `class A { var x = 1; m() {} static s() {} } var a = A(); a.y = 2; print fields(a); print methods(A); print hasField(a, "y"); print hasField(a, "z");`,
+			Output: "[\"x\", \"y\"]\n[\"m\"]\ntrue\nfalse\n"},
+		{Name: "get and set", Source: `class A { var x = 1; } var a = A(); print getField(a, "x"); setField(a, "x", 5); print a.x;`, Output: "1\n5\n"},
+		{Name: "set strict", Source: "strict class A {}\nsetField(A(), \"q\", 1);", Err: "[line 2] runtime error : strict class A has no field q"},
+		{Name: "missing field", Source: `class A {} getField(A(), "q");`, Err: "A has no field q"},
+		{Name: "shadow", Source: `var fields = [1]; func type(x) { return "mine"; } var set = 2; print fields; print type(1); print set; print className(fields);`,
+			Err: "className need class or instance, got list", Output: "[1]\nmine\n2\n"},
+		{Name: "not a class", Source: `className(1);`, Err: "className need class or instance, got int"},
+	})
+}
@@ -7,25 +7,32 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"strconv"
 )
 
 type Scanner struct {
-	Source string
-	Index  int
-	Line   int
+	Source    []rune // 按 utf8 解码后的字符
+	Index     int
+	Line      int
+	LineStart int // 当前行首字符的下标，用于计算列号
 }
 
 func (s *Scanner) ScanTokens() []*Token {
 	tokens := make([]*Token, 0)
 	s.Index = 0
 	s.Line = 1
+	s.LineStart = 0
 	for s.Index < len(s.Source) {
+		column := s.Column()
 		if token := s.ScanToken(); token != nil {
+			token.Column = column // 记录 token 起始列
 			tokens = append(tokens, token)
 		}
 	}
-	tokens = append(tokens, NewToken(EOF, "", nil, s.Line)) // 添加截断标记
+	eof := NewToken(EOF, "", nil, s.Line) // 添加截断标记
+	eof.Column = s.Column()
+	tokens = append(tokens, eof)
 	return tokens
 }
 
@@ -40,12 +47,24 @@ func (s *Scanner) ScanToken() *Token {
 		return NewToken(LEFT2, "{", nil, s.Line)
 	case '}':
 		return NewToken(RIGHT2, "}", nil, s.Line)
+	case '[':
+		return NewToken(LEFT3, "[", nil, s.Line)
+	case ']':
+		return NewToken(RIGHT3, "]", nil, s.Line)
 	case ',':
 		return NewToken(COMMA, ",", nil, s.Line)
 	case '.':
+		if s.Match('.') {
+			if s.Match('.') {
+				return NewToken(DOT3, "...", nil, s.Line)
+			}
+			return NewToken(DOT2, "..", nil, s.Line)
+		}
 		return NewToken(DOT, ".", nil, s.Line)
 	case ';':
 		return NewToken(SEMI, ";", nil, s.Line)
+	case ':':
+		return NewToken(COLON, ":", nil, s.Line)
 	case '+':
 		return NewToken(ADD, "+", nil, s.Line)
 	case '-':
@@ -56,7 +75,6 @@ func (s *Scanner) ScanToken() *Token {
 		if s.Match('/') { // 注释  暂时只支持单行注释
 			for s.HasMore() && s.Read() != '\n' { // 移除全部注释
 			}
-			s.Line++
 			return nil
 		}
 		return NewToken(DIV, "/", nil, s.Line)
@@ -80,40 +98,74 @@ func (s *Scanner) ScanToken() *Token {
 			return NewToken(GE, ">=", nil, s.Line)
 		}
 		return NewToken(GT, ">", nil, s.Line)
-	case '"': // 字符串处理 只能处理单行字符串
+	case '#': // #name 私有成员名
+		if !s.HasMore() || !IsAlpha(s.Get()) {
+			s.Error("expect name after #")
+			return nil
+		}
 		buff := bytes.Buffer{}
-		for s.HasMore() {
-			if ch = s.Read(); ch != '"' {
-				buff.WriteByte(ch)
-			} else {
-				return NewToken(STR, buff.String(), buff.String(), s.Line)
-			}
+		buff.WriteRune(ch)
+		for s.HasMore() && IsAlphaNum(s.Get()) {
+			buff.WriteRune(s.Read())
 		}
-		Error(s.Line, "no end string")
-		return nil
+		return NewToken(PRIVATE, buff.String(), nil, s.Line)
+	case '"': // 字符串处理 支持转义与 ${expr} 插值
+		return s.ScanString()
 	case ' ', '\t', '\r':
 		return nil // skip
-	case '\n':
-		s.Line++
+	case '\n': // 行号在 Read 中统一处理
 		return nil
 	default:
-		if IsDigit(ch) {
+		if IsDigit(ch) { // 不含小数点的为整数 int64 否则为小数 float64，后缀 n 为大整数 m 为精确小数
 			buff := bytes.Buffer{}
-			buff.WriteByte(ch)
-			for s.HasMore() && (IsDigit(s.Get()) || s.Get() == '.') {
-				buff.WriteByte(s.Read())
+			buff.WriteRune(ch)
+			for s.HasMore() && IsDigit(s.Get()) {
+				buff.WriteRune(s.Read())
+			}
+			isFloat := false
+			if s.HasMore() && s.Get() == '.' && s.Index+1 < len(s.Source) && IsDigit(s.Source[s.Index+1]) {
+				isFloat = true
+				buff.WriteRune(s.Read())
+				for s.HasMore() && IsDigit(s.Get()) {
+					buff.WriteRune(s.Read())
+				}
+			}
+			num := buff.String()
+			if !isFloat && s.MatchSuffix('n') {
+				res, ok := new(big.Int).SetString(num, 10)
+				if !ok {
+					s.Error(fmt.Sprintf("err num of %sn", num))
+					return nil
+				}
+				return NewToken(NUM, num+"n", res, s.Line)
+			}
+			if s.MatchSuffix('m') {
+				res, ok := new(big.Rat).SetString(num)
+				if !ok {
+					s.Error(fmt.Sprintf("err num of %sm", num))
+					return nil
+				}
+				return NewToken(NUM, num+"m", NewScaleDecimal(res, TextScale(num)), s.Line)
 			}
-			res, err := strconv.ParseFloat(buff.String(), 64)
+			if isFloat {
+				res, err := strconv.ParseFloat(num, 64)
+				if err != nil {
+					s.Error(fmt.Sprintf("err num of %s err = %v", num, err))
+					return nil
+				}
+				return NewToken(NUM, num, res, s.Line)
+			}
+			res, err := strconv.ParseInt(num, 10, 64)
 			if err != nil {
-				Error(s.Line, fmt.Sprintf("err num of %s err = %v", buff.String(), err))
+				s.Error(fmt.Sprintf("err num of %s err = %v", num, err))
 				return nil
 			}
-			return NewToken(NUM, buff.String(), res, s.Line)
+			return NewToken(NUM, num, res, s.Line)
 		} else if IsAlpha(ch) {
 			buff := bytes.Buffer{}
-			buff.WriteByte(ch)
-			for s.HasMore() && (IsAlpha(s.Get()) || IsDigit(s.Get())) {
-				buff.WriteByte(s.Read())
+			buff.WriteRune(ch)
+			for s.HasMore() && IsAlphaNum(s.Get()) {
+				buff.WriteRune(s.Read())
 			}
 			str := buff.String()
 			if type0, ok := Keywords[str]; ok { // 关键字处理
@@ -121,21 +173,96 @@ func (s *Scanner) ScanToken() *Token {
 			}
 			return NewToken(ID, buff.String(), nil, s.Line) // 变量处理
 		}
-		Error(s.Line, fmt.Sprintf("unknown of %c", ch))
+		s.Error(fmt.Sprintf("unknown of %c", ch))
 		return nil
 	}
 }
 
-func (s *Scanner) Read() uint8 {
+func (s *Scanner) ScanString() *Token {
+	start := s.Index
+	parts := make([]any, 0) // 插值字符串的各个部分 文本为 string 插值表达式为 []*Token
+	buff := bytes.Buffer{}
+	for s.HasMore() {
+		ch := s.Read()
+		switch {
+		case ch == '"':
+			if len(parts) == 0 { // 没有插值 普通字符串
+				return NewToken(STR, buff.String(), buff.String(), s.Line)
+			}
+			parts = append(parts, buff.String())
+			return NewToken(TMPL, string(s.Source[start:s.Index-1]), parts, s.Line)
+		case ch == '\\':
+			if !s.HasMore() {
+				break
+			}
+			switch esc := s.Read(); esc {
+			case 'n':
+				buff.WriteRune('\n')
+			case 't':
+				buff.WriteRune('\t')
+			case 'r':
+				buff.WriteRune('\r')
+			case '"', '\\', '$':
+				buff.WriteRune(esc)
+			default:
+				s.Error(fmt.Sprintf("unknown escape \\%c", esc))
+			}
+		case ch == '$' && s.Match('{'):
+			tokens := s.ScanInterpolation()
+			if tokens == nil {
+				return nil
+			}
+			parts = append(parts, buff.String(), tokens)
+			buff.Reset()
+		default:
+			buff.WriteRune(ch)
+		}
+	}
+	s.Error("no end string")
+	return nil
+}
+
+func (s *Scanner) ScanInterpolation() []*Token { // 扫描 ${ 之后直到匹配的 } 的全部 token，嵌套的字符串会递归处理
+	tokens := make([]*Token, 0)
+	depth := 0
+	for s.HasMore() {
+		column := s.Column()
+		token := s.ScanToken()
+		if token == nil {
+			continue
+		}
+		token.Column = column
+		if token.Type == LEFT2 {
+			depth++
+		} else if token.Type == RIGHT2 {
+			if depth == 0 {
+				eof := NewToken(EOF, "", nil, s.Line)
+				eof.Column = column
+				return append(tokens, eof)
+			}
+			depth--
+		}
+		tokens = append(tokens, token)
+	}
+	s.Error("no end interpolation")
+	return nil
+}
+
+func (s *Scanner) Read() rune {
 	s.Index++
-	return s.Source[s.Index-1]
+	ch := s.Source[s.Index-1]
+	if ch == '\n' { // 换行统一在这里处理
+		s.Line++
+		s.LineStart = s.Index
+	}
+	return ch
 }
 
-func (s *Scanner) Get() uint8 {
+func (s *Scanner) Get() rune {
 	return s.Source[s.Index]
 }
 
-func (s *Scanner) Match(ch uint8) bool {
+func (s *Scanner) Match(ch rune) bool {
 	if !s.HasMore() {
 		return false
 	}
@@ -146,14 +273,33 @@ func (s *Scanner) Match(ch uint8) bool {
 	return true
 }
 
+func (s *Scanner) MatchSuffix(ch rune) bool { // 数字后缀，后缀后面不能紧跟标识符字符
+	if !s.HasMore() || s.Get() != ch {
+		return false
+	}
+	if s.Index+1 < len(s.Source) && IsAlphaNum(s.Source[s.Index+1]) {
+		return false
+	}
+	s.Index++
+	return true
+}
+
 func (s *Scanner) HasMore() bool {
 	return s.Index < len(s.Source)
 }
 
-func (s *Scanner) UnRead() {
+func (s *Scanner) UnRead() { // 不能回退换行符
 	s.Index--
 }
 
+func (s *Scanner) Column() int { // 列号按字符计算 从 1 开始
+	return s.Index - s.LineStart + 1
+}
+
+func (s *Scanner) Error(msg string) { // 报告最后读取字符所在的列
+	Report(s.Line, fmt.Sprintf("at column %d", s.Column()-1), msg)
+}
+
 func NewScanner(source string) *Scanner {
-	return &Scanner{Source: source}
+	return &Scanner{Source: []rune(source)}
 }
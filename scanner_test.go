@@ -0,0 +1,32 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestScanner(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "unicode identifier", Source: `var 名字 = "世界"; var café = 1; print 名字; print café;`, Output: "世界\n1\n"},
+		{Name: "unicode comment", Source: "print 1; // 注释 中文\nprint 2;", Output: "1\n2\n"},
+		{Name: "rune column", Source: `print "中文" + 1 ~;`, Output: "[line 1] Error at column 16 : unknown of ~\n中文1\n"},
+		{Name: "rune column after identifier", Source: "var a = 1;\nvar 中文 = @;",
+			Output: "[line 2] Error at column 10 : unknown of @\n", Err: "invalid token"},
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "expressions", Source: `var user = {"name": "sk"}; var n = 2; print "Hello ${user["name"]}, you have ${n} items";`,
+			Output: "Hello sk, you have 2 items\n"},
+		{Name: "print rules", Source: `print "${nil} ${true} ${1.5} ${[1, "x"]}";`, Output: "nil true 1.5 [1, \"x\"]\n"},
+		{Name: "nested string", Source: `print "a${"b${1 + 1}c"}d";`, Output: "ab2cd\n"},
+		{Name: "map literal", Source: `print "v ${{"k": 1}["k"]}";`, Output: "v 1\n"},
+		{Name: "escape dollar", Source: `print "cost \${x} \$5 $";`, Output: "cost ${x} $5 $\n"},
+		{Name: "unknown escape", Source: `print "a\qb";`, Output: "[line 1] Error at column 10 : unknown escape \\q\nab\n"},
+		{Name: "one expression", Source: `print "${1 2}";`, Err: "invalid token"},
+		{Name: "unterminated", Source: `print "${1 + ";`, Err: "invalid token",
+			Output: "[line 1] Error at column 15 : no end string\n[line 1] Error at column 15 : no end interpolation\n"},
+	})
+}
@@ -0,0 +1,115 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type HashSet struct { // 本地集合类型 set() 基于字典实现 按插入顺序迭代
+	Items *Map
+}
+
+func NewHashSet() *HashSet {
+	return &HashSet{Items: NewMap()}
+}
+
+func (h *HashSet) Get(name string) any { // 集合只有方法 没有字段
+	switch name {
+	case "add":
+		return NewNativeFunc("add", 1, func(args []any) any {
+			h.Add(callToken, args[0])
+			return nil
+		})
+	case "remove": // 返回是否存在并删除
+		return NewNativeFunc("remove", 1, func(args []any) any {
+			return h.Items.Delete(callToken, args[0])
+		})
+	case "has":
+		return NewNativeFunc("has", 1, func(args []any) any {
+			return h.Contains(callToken, args[0])
+		})
+	case "len":
+		return NewNativeFunc("len", 0, func(args []any) any {
+			return int64(len(h.Items.Entries))
+		})
+	case "values":
+		return NewNativeFunc("values", 0, func(args []any) any {
+			return NewList(h.Values())
+		})
+	case "union":
+		return NewNativeFunc("union", 1, func(args []any) any {
+			other := ToHashSet(args[0])
+			res := NewHashSet()
+			for _, item := range h.Values() {
+				res.Add(callToken, item)
+			}
+			for _, item := range other.Values() {
+				res.Add(callToken, item)
+			}
+			return res
+		})
+	case "intersection":
+		return NewNativeFunc("intersection", 1, func(args []any) any {
+			other := ToHashSet(args[0])
+			res := NewHashSet()
+			for _, item := range h.Values() {
+				if other.Contains(callToken, item) {
+					res.Add(callToken, item)
+				}
+			}
+			return res
+		})
+	case "difference":
+		return NewNativeFunc("difference", 1, func(args []any) any {
+			other := ToHashSet(args[0])
+			res := NewHashSet()
+			for _, item := range h.Values() {
+				if !other.Contains(callToken, item) {
+					res.Add(callToken, item)
+				}
+			}
+			return res
+		})
+	default:
+		panic(fmt.Sprintf("set no method name %s", name))
+	}
+}
+
+func (h *HashSet) Set(name *Token, val any) {
+	RuntimeError(name, fmt.Sprintf("can't set field %s on set", name.Lexeme))
+}
+
+func (h *HashSet) Add(token *Token, val any) {
+	h.Items.Put(token, val, nil)
+}
+
+func (h *HashSet) Contains(token *Token, val any) bool {
+	return h.Items.Contains(token, val)
+}
+
+func (h *HashSet) Values() []any {
+	res := make([]any, 0, len(h.Items.Entries))
+	for _, entry := range h.Items.Entries {
+		res = append(res, entry.Key)
+	}
+	return res
+}
+
+func (h *HashSet) String() string {
+	items := make([]string, 0, len(h.Items.Entries))
+	for _, item := range h.Values() {
+		items = append(items, ReprString(item))
+	}
+	return "set(" + strings.Join(items, ", ") + ")"
+}
+
+func ToHashSet(val any) *HashSet {
+	if res, ok := val.(*HashSet); ok {
+		return res
+	}
+	panic(fmt.Sprintf("%s not a set", TypeName(val)))
+}
@@ -4,10 +4,15 @@
 */
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
 var (
-	currEnv = NewEnvironment()
+	currEnv           = NewEnvironment()
+	Output  io.Writer = os.Stdout // print 的输出位置 测试时替换
 )
 
 type IStmt interface {
@@ -32,7 +37,7 @@ type Print struct { // print expression ;
 
 func (p *Print) Exec() {
 	val := p.Expression.GetValue()
-	fmt.Println(val)
+	fmt.Fprintln(Output, ToString(val))
 }
 
 func NewPrint(expression IExpr) *Print {
@@ -56,6 +61,40 @@ func NewVar(name *Token, expr IExpr) *Var {
 	return &Var{Name: name, Expr: expr}
 }
 
+type VarUnpack struct { // var (a, b) = expr ; var [a, ...b] = expr ;
+	Token *Token // = 用于报错定位
+	Names []*Token
+	Rest  *Token // 剩余元素打包为列表 可以为空
+	Expr  IExpr
+}
+
+func (v *VarUnpack) Exec() {
+	items, rest := Unpack(v.Token, v.Expr.GetValue(), len(v.Names), v.Rest != nil)
+	for i, name := range v.Names {
+		currEnv.Define(name.Lexeme, items[i])
+	}
+	if v.Rest != nil {
+		currEnv.Define(v.Rest.Lexeme, rest)
+	}
+}
+
+func NewVarUnpack(token *Token, names []*Token, rest *Token, expr IExpr) *VarUnpack {
+	return &VarUnpack{Token: token, Names: names, Rest: rest, Expr: expr}
+}
+
+func Unpack(token *Token, val any, size int, hasRest bool) ([]any, *List) { // 解构可迭代对象 数量不匹配时报错
+	items := make([]any, 0)
+	iter := GetIterator(val)
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		items = append(items, item)
+	}
+	if len(items) < size || (!hasRest && len(items) != size) {
+		RuntimeError(token, fmt.Sprintf("can't unpack %d values into %d vars", len(items), size))
+	}
+	rest := make([]any, 0)
+	return items[:size], NewList(append(rest, items[size:]...))
+}
+
 type Assign struct { // name = expr ;
 	Name *Token
 	Expr IExpr
@@ -70,6 +109,40 @@ func NewAssign(name *Token, expr IExpr) *Assign {
 	return &Assign{Name: name, Expr: expr}
 }
 
+type AssignUnpack struct { // [a, obj.b, xs[0], ...c] = expr ;
+	Token   *Token  // = 用于报错定位
+	Targets []IExpr // 只能是 Variable Get Index
+	Rest    IExpr   // 可以为空
+	Expr    IExpr
+}
+
+func (a *AssignUnpack) Exec() {
+	items, rest := Unpack(a.Token, a.Expr.GetValue(), len(a.Targets), a.Rest != nil)
+	for i, target := range a.Targets {
+		AssignTarget(target, items[i])
+	}
+	if a.Rest != nil {
+		AssignTarget(a.Rest, rest)
+	}
+}
+
+func NewAssignUnpack(token *Token, targets []IExpr, rest IExpr, expr IExpr) *AssignUnpack {
+	return &AssignUnpack{Token: token, Targets: targets, Rest: rest, Expr: expr}
+}
+
+func AssignTarget(target IExpr, val any) { // 与 Set SetIndex 使用相同的赋值逻辑
+	switch temp := target.(type) {
+	case *Variable:
+		currEnv.Assign(temp.Name.Lexeme, val)
+	case *Get:
+		SetField(temp.Object.GetValue(), temp.Name, val)
+	case *Index:
+		SetIndexValue(temp.Token, temp.Object.GetValue(), temp.Index.GetValue(), val)
+	default:
+		panic(fmt.Sprintf("invalid unpack target %v", target))
+	}
+}
+
 type Set struct { // Object.Name=Expr
 	Object IExpr
 	Name   *Token
@@ -78,18 +151,49 @@ type Set struct { // Object.Name=Expr
 
 func (s *Set) Exec() {
 	temp := s.Object.GetValue()
-	if inst, ok := temp.(IInstance); ok {
-		val := s.Expr.GetValue()
-		inst.Set(s.Name.Lexeme, val)
+	SetField(temp, s.Name, s.Expr.GetValue())
+}
+
+func SetField(obj any, name *Token, val any) { // obj.name = val 私有成员需要检查访问权限
+	if name.Type == PRIVATE {
+		SetPrivate(name, obj, val)
+		return
+	}
+	if inst, ok := obj.(IInstance); ok {
+		inst.Set(name, val)
 		return
 	}
-	panic(fmt.Sprintf("obj %v not a Instance", temp))
+	RuntimeError(name, fmt.Sprintf("%s not an instance, can't set field %s", TypeName(obj), name.Lexeme))
 }
 
 func NewSet(object IExpr, name *Token, expr IExpr) *Set {
 	return &Set{Object: object, Name: name, Expr: expr}
 }
 
+type SetIndex struct { // Object[Index]=Expr
+	Object, Index IExpr
+	Token         *Token
+	Expr          IExpr
+}
+
+func (s *SetIndex) Exec() {
+	temp := s.Object.GetValue()
+	index := s.Index.GetValue()
+	SetIndexValue(s.Token, temp, index, s.Expr.GetValue())
+}
+
+func SetIndexValue(token *Token, obj any, index any, val any) { // obj[index] = val
+	if temp, ok := obj.(IIndex); ok {
+		temp.SetIndex(token, index, val)
+		return
+	}
+	RuntimeError(token, fmt.Sprintf("%s can't be indexed", TypeName(obj)))
+}
+
+func NewSetIndex(object IExpr, index IExpr, token *Token, expr IExpr) *SetIndex {
+	return &SetIndex{Object: object, Index: index, Token: token, Expr: expr}
+}
+
 type Block struct { // { Declaration*  }
 	Statements []IStmt
 }
@@ -108,24 +212,26 @@ func (b *Block) Exec() {
 }
 
 type If struct { // if ( IExpr ) { IfBranch } else { ElseBranch }
+	Token                *Token // 用于报错定位
 	Condition            IExpr
 	IfBranch, ElseBranch IStmt
 }
 
 func (i *If) Exec() {
 	val := i.Condition.GetValue()
-	if val.(bool) {
+	if MustCond(i.Token, val, "if condition") {
 		i.IfBranch.Exec()
 	} else if i.ElseBranch != nil {
 		i.ElseBranch.Exec()
 	}
 }
 
-func NewIf(condition IExpr, ifBranch IStmt, elseBranch IStmt) *If {
-	return &If{Condition: condition, IfBranch: ifBranch, ElseBranch: elseBranch}
+func NewIf(token *Token, condition IExpr, ifBranch IStmt, elseBranch IStmt) *If {
+	return &If{Token: token, Condition: condition, IfBranch: ifBranch, ElseBranch: elseBranch}
 }
 
 type For struct { // for(Init?;Condition?;Change?){Body?} ;不能省略
+	Token        *Token // 用于报错定位
 	Init         IStmt
 	Condition    IExpr
 	Change, Body IStmt
@@ -137,7 +243,7 @@ func (f *For) Exec() {
 	if f.Init != nil {
 		f.Init.Exec()
 	}
-	for f.Condition == nil || f.Condition.GetValue().(bool) { // 条件为空视为 true
+	for f.Condition == nil || MustCond(f.Token, f.Condition.GetValue(), "for condition") { // 条件为空视为 true
 		f.Body.Exec()
 		if f.Change != nil { // 执行变更
 			f.Change.Exec()
@@ -146,22 +252,69 @@ func (f *For) Exec() {
 	currEnv = oldEnv // 移除作用域
 }
 
-func NewFor(init IStmt, condition IExpr, change IStmt, body IStmt) *For {
-	return &For{Init: init, Condition: condition, Change: change, Body: body}
+func NewFor(token *Token, init IStmt, condition IExpr, change IStmt, body IStmt) *For {
+	return &For{Token: token, Init: init, Condition: condition, Change: change, Body: body}
+}
+
+type ForIn struct { // for(var Names in Iterable){Body} Names 为两个时每个元素需要是 [k, v]
+	Names    []*Token
+	Iterable IExpr
+	Body     IStmt
+}
+
+func (f *ForIn) Exec() {
+	var iter IIterator
+	if len(f.Names) == 1 {
+		iter = GetIterator(f.Iterable.GetValue())
+	} else {
+		iter = GetEntryIterator(f.Iterable.GetValue())
+	}
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		oldEnv := currEnv
+		currEnv = NewEnvironmentWithParent(currEnv) // 每次迭代单独的作用域 闭包可以捕获当次的值
+		if len(f.Names) == 1 {
+			currEnv.Define(f.Names[0].Lexeme, item)
+		} else {
+			entry, ok := item.(*List)
+			if !ok || len(entry.Items) != 2 {
+				RuntimeError(f.Names[0], fmt.Sprintf("for-in element %s can't unpack to 2 vars", ToString(item)))
+			}
+			currEnv.Define(f.Names[0].Lexeme, entry.Items[0])
+			currEnv.Define(f.Names[1].Lexeme, entry.Items[1])
+		}
+		f.Body.Exec()
+		currEnv = oldEnv
+	}
+}
+
+func NewForIn(names []*Token, iterable IExpr, body IStmt) *ForIn {
+	return &ForIn{Names: names, Iterable: iterable, Body: body}
 }
 
 type Function struct {
-	Name   *Token
-	Params []*Token
-	Body   IStmt
+	Name     *Token
+	Params   []*Token
+	Defaults []IExpr // 与 Params 一一对应 没有默认值的为 nil
+	Rest     *Token  // ...rest 剩余参数 可以为空
+	Body     IStmt
+	Supers   []string // 函数体中 super. 之后的名称
 }
 
 func (f *Function) Exec() {
-	currEnv.Define(f.Name.Lexeme, NewBaseCall(f.Params, f.Body, currEnv))
+	currEnv.Define(f.Name.Lexeme, NewBaseCall(f, currEnv))
+}
+
+func (f *Function) CallsSuper(name string) bool {
+	for _, item := range f.Supers {
+		if item == name {
+			return true
+		}
+	}
+	return false
 }
 
-func NewFunction(name *Token, params []*Token, body IStmt) *Function {
-	return &Function{Name: name, Params: params, Body: body}
+func NewFunction(name *Token, params []*Token, defaults []IExpr, rest *Token, body IStmt) *Function {
+	return &Function{Name: name, Params: params, Defaults: defaults, Rest: rest, Body: body}
 }
 
 type Return struct {
@@ -183,8 +336,18 @@ func NewReturn(expr IExpr) *Return {
 }
 
 type Class struct {
-	Name, Parent *Token
-	Methods      []*Function
+	Name, Parent  *Token
+	Traits        []*Token
+	Methods       []*Function
+	StaticMethods []*Function
+	StaticFields  []*Var
+	Getters       []*Function
+	Setters       []*Function
+	Abstract      bool
+	Abstracts     []*Function // 抽象方法 没有方法体
+	Interfaces    []*Token
+	Fields        []*Var
+	Strict        bool
 }
 
 func (c *Class) Exec() {
@@ -193,12 +356,197 @@ func (c *Class) Exec() {
 		parent = currEnv.Get(c.Parent.Lexeme).(*BaseClass)
 	}
 	methods := make(map[string]*BaseCall, len(c.Methods))
+	class := NewBaseClass(c.Name.Lexeme, parent, methods)
+	classEnv := NewEnvironmentWithParent(currEnv) // 类中定义的方法都能找到所属的类
+	classEnv.Define(OWNER_KEY, class)
 	for _, method := range c.Methods {
-		methods[method.Name.Lexeme] = NewBaseCall(method.Params, method.Body, currEnv)
+		methods[method.Name.Lexeme] = NewBaseCall(method, classEnv)
+	}
+	if init, ok := methods["init"]; ok {
+		init.Init = true
+	}
+	if len(c.Traits) > 0 {
+		for _, name := range c.Traits {
+			trait, ok := currEnv.Get(name.Lexeme).(*BaseTrait)
+			if !ok {
+				RuntimeError(name, fmt.Sprintf("%s not a trait", name.Lexeme))
+			}
+			class.Traits = append(class.Traits, trait)
+		}
+		class.CheckTraitConflict()
+		class.Linearize()
+	}
+	for _, method := range c.StaticMethods {
+		class.StaticMethods[method.Name.Lexeme] = NewBaseCall(method, classEnv)
+	}
+	for _, getter := range c.Getters {
+		class.Getters[getter.Name.Lexeme] = NewBaseCall(getter, classEnv)
+	}
+	for _, setter := range c.Setters {
+		class.Setters[setter.Name.Lexeme] = NewBaseCall(setter, classEnv)
+	}
+	class.Abstract = c.Abstract
+	for _, method := range c.Abstracts {
+		class.Abstracts[method.Name.Lexeme] = method
+	}
+	for _, name := range c.Interfaces {
+		inter, ok := currEnv.Get(name.Lexeme).(*BaseInterface)
+		if !ok {
+			RuntimeError(name, fmt.Sprintf("%s not an interface", name.Lexeme))
+		}
+		class.Interfaces = append(class.Interfaces, inter)
+	}
+	class.FieldDecls = c.Fields
+	class.DefineEnv = classEnv
+	class.Strict = c.Strict
+	class.CheckAbstract() // 定义时检查 而不是调用时
+	currEnv.Define(c.Name.Lexeme, class)
+	if len(c.StaticFields) > 0 { // 静态字段在类定义后按顺序初始化 初始化表达式中 this 为类本身
+		oldEnv := currEnv
+		currEnv = NewEnvironmentWithParent(classEnv)
+		currEnv.Define("this", class)
+		for _, field := range c.StaticFields {
+			var val any
+			if field.Expr != nil {
+				val = field.Expr.GetValue()
+			}
+			class.StaticFields[field.Name.Lexeme] = val
+		}
+		currEnv = oldEnv
 	}
-	currEnv.Define(c.Name.Lexeme, NewBaseClass(c.Name.Lexeme, parent, methods))
 }
 
 func NewClass(name *Token, parent *Token, methods []*Function) *Class {
 	return &Class{Name: name, Parent: parent, Methods: methods}
 }
+
+type Trait struct {
+	Name    *Token
+	Methods []*Function
+}
+
+func (t *Trait) Exec() {
+	methods := make(map[string]*BaseCall, len(t.Methods))
+	trait := NewBaseTrait(t.Name.Lexeme, methods)
+	traitEnv := NewEnvironmentWithParent(currEnv) // trait 中的 super 从该 trait 之后查找
+	traitEnv.Define(OWNER_KEY, trait)
+	for _, method := range t.Methods {
+		methods[method.Name.Lexeme] = NewBaseCall(method, traitEnv)
+	}
+	if init, ok := methods["init"]; ok {
+		init.Init = true
+	}
+	currEnv.Define(t.Name.Lexeme, trait)
+}
+
+func NewTrait(name *Token, methods []*Function) *Trait {
+	return &Trait{Name: name, Methods: methods}
+}
+
+type Interface struct {
+	Name    *Token
+	Parents []*Token
+	Methods []*Function // 只有签名
+}
+
+func (i *Interface) Exec() {
+	parents := make([]*BaseInterface, 0, len(i.Parents))
+	for _, name := range i.Parents {
+		parent, ok := currEnv.Get(name.Lexeme).(*BaseInterface)
+		if !ok {
+			RuntimeError(name, fmt.Sprintf("%s not an interface", name.Lexeme))
+		}
+		parents = append(parents, parent)
+	}
+	methods := make(map[string]*Function, len(i.Methods))
+	for _, method := range i.Methods {
+		methods[method.Name.Lexeme] = method
+	}
+	currEnv.Define(i.Name.Lexeme, NewBaseInterface(i.Name.Lexeme, parents, methods))
+}
+
+func NewInterface(name *Token, parents []*Token, methods []*Function) *Interface {
+	return &Interface{Name: name, Parents: parents, Methods: methods}
+}
+
+type EnumVariant struct {
+	Name   *Token
+	Fields []*Token // 为 nil 表示没有字段 成员本身就是实例
+}
+
+func NewEnumVariant(name *Token, fields []*Token) *EnumVariant {
+	return &EnumVariant{Name: name, Fields: fields}
+}
+
+type Enum struct {
+	Name     *Token
+	Variants []*EnumVariant
+	Methods  []*Function
+}
+
+// 枚举本身是不能实例化的类 每个成员是它的子类，作为静态字段 Color.Red Shape.Circle(1)
+// 没有字段的成员只有一个实例，有字段的成员是值类型按字段比较
+func (e *Enum) Exec() {
+	methods := make(map[string]*BaseCall, len(e.Methods))
+	enum := NewBaseClass(e.Name.Lexeme, nil, methods)
+	enum.Abstract = true
+	enum.Variants = make([]any, 0, len(e.Variants))
+	classEnv := NewEnvironmentWithParent(currEnv)
+	classEnv.Define(OWNER_KEY, enum)
+	for _, method := range e.Methods {
+		methods[method.Name.Lexeme] = NewBaseCall(method, classEnv)
+	}
+	for _, variant := range e.Variants {
+		name := variant.Name.Lexeme
+		if _, ok := enum.StaticFields[name]; ok {
+			RuntimeError(variant.Name, fmt.Sprintf("repeat variant %s in enum %s", name, e.Name.Lexeme))
+		}
+		class := NewBaseClass(e.Name.Lexeme+"."+name, enum, make(map[string]*BaseCall))
+		class.ValueFields = make([]string, 0, len(variant.Fields))
+		for _, field := range variant.Fields {
+			class.ValueFields = append(class.ValueFields, field.Lexeme)
+		}
+		var val any = class
+		if variant.Fields == nil {
+			val = NewBaseInstance(class)
+		}
+		enum.StaticFields[name] = val
+		enum.Variants = append(enum.Variants, val)
+	}
+	currEnv.Define(e.Name.Lexeme, enum)
+}
+
+func NewEnum(name *Token, variants []*EnumVariant, methods []*Function) *Enum {
+	return &Enum{Name: name, Variants: variants, Methods: methods}
+}
+
+type Record struct {
+	Name    *Token
+	Fields  []*Token
+	Methods []*Function
+}
+
+// record 是值类型的类 构造方法按字段顺序生成 按字段比较 哈希 打印
+func (r *Record) Exec() {
+	methods := make(map[string]*BaseCall, len(r.Methods))
+	class := NewBaseClass(r.Name.Lexeme, nil, methods)
+	class.ValueFields = make([]string, 0, len(r.Fields))
+	for _, field := range r.Fields {
+		for _, name := range class.ValueFields {
+			if name == field.Lexeme {
+				RuntimeError(field, fmt.Sprintf("repeat field %s in record %s", name, r.Name.Lexeme))
+			}
+		}
+		class.ValueFields = append(class.ValueFields, field.Lexeme)
+	}
+	classEnv := NewEnvironmentWithParent(currEnv)
+	classEnv.Define(OWNER_KEY, class)
+	for _, method := range r.Methods {
+		methods[method.Name.Lexeme] = NewBaseCall(method, classEnv)
+	}
+	currEnv.Define(r.Name.Lexeme, class)
+}
+
+func NewRecord(name *Token, fields []*Token, methods []*Function) *Record {
+	return &Record{Name: name, Fields: fields, Methods: methods}
+}
@@ -0,0 +1,23 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestUnpack(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "multiple return", Source: `func divmod(a, b) { return a / b, a - a / b * b; } var (q, r) = divmod(7, 2); print q; print r;`,
+			Output: "3\n1\n"},
+		{Name: "rest", Source: `var [first, ...rest] = [1, 2, 3]; print first; print rest;`, Output: "1\n[2, 3]\n"},
+		{Name: "assign targets", Source: `class A {} var a = A(); var xs = [0, 0]; var b = 0;
+[a.x, xs[1], b] = [1, 2, 3]; print a.x; print xs; print b;`, Output: "1\n[0, 2]\n3\n"},
+		{Name: "swap", Source: `var a = 1; var b = 2; [a, b] = [b, a]; print a; print b;`, Output: "2\n1\n"},
+		{Name: "var mismatch", Source: "var (a, b) =\n[1, 2, 3];", Err: "[line 1] runtime error : can't unpack 3 values into 2 vars"},
+		{Name: "assign mismatch", Source: "var a = 0; var b = 0;\n[a, b] = [1];", Err: "[line 2] runtime error : can't unpack 1 values into 2 vars"},
+		{Name: "rest too few", Source: `var [a, b, ...c] = [1];`, Err: "[line 1] runtime error : can't unpack 1 values into 2 vars"},
+		{Name: "target not instance", Source: "var n = 1;\n[n.x] = [1];", Err: "[line 2] runtime error : int not an instance, can't set field x"},
+		{Name: "target not indexable", Source: "var n = 1;\n[n[0]] = [1];", Err: "[line 2] runtime error : int can't be indexed"},
+	})
+}
@@ -15,13 +15,18 @@ const (
 	RIGHT  // )
 	LEFT2  // {
 	RIGHT2 // }
+	LEFT3  // [
+	RIGHT3 // ]
 	ADD    // +
 	SUB    // -
 	MUL    // *
 	DIV    // /
 	COMMA  // ,
 	DOT    // .
+	DOT2   // ..
+	DOT3   // ...
 	SEMI   // ;
+	COLON  // :
 	// One or two character tokens.
 	NOT    // !
 	NE     // !=
@@ -32,44 +37,62 @@ const (
 	LT     // <
 	LE     // <=
 	// Literals.
-	ID  // var
-	STR // string
-	NUM // int float
+	ID      // var
+	PRIVATE // #name 私有成员
+	STR     // string
+	NUM     // int float
+	TMPL    // "a${b}c" 插值字符串
 	// Keywords.
+	ABSTRACT
 	AND
 	CLASS
 	ELSE
+	ENUM
 	FALSE
 	FUNC
 	FOR
 	IF
+	IN
+	INTERFACE
+	IS
 	NIL
 	OR
 	PRINT
+	RECORD
 	RETURN
+	STATIC
 	SUPER
 	THIS
+	TRAIT
 	TRUE
 	VAR
 )
 
 var (
 	Keywords = map[string]TokenType{
-		"and":    AND,
-		"class":  CLASS,
-		"else":   ELSE,
-		"false":  FALSE,
-		"for":    FOR,
-		"func":   FUNC,
-		"if":     IF,
-		"nil":    NIL,
-		"or":     OR,
-		"print":  PRINT,
-		"return": RETURN,
-		"super":  SUPER,
-		"this":   THIS,
-		"true":   TRUE,
-		"var":    VAR,
+		"abstract":  ABSTRACT,
+		"and":       AND,
+		"class":     CLASS,
+		"else":      ELSE,
+		"enum":      ENUM,
+		"false":     FALSE,
+		"for":       FOR,
+		"func":      FUNC,
+		"if":        IF,
+		"in":        IN,
+		"interface": INTERFACE,
+		"is":        IS,
+		"nil":       NIL,
+		"or":        OR,
+		"print":     PRINT,
+		"record":    RECORD,
+		"return":    RETURN,
+		"static":    STATIC,
+		"super":     SUPER,
+		"this":      THIS,
+		"trait":     TRAIT,
+		"true":      TRUE,
+		"var":       VAR,
 	}
 )
 
@@ -78,10 +101,11 @@ type Token struct {
 	Lexeme string // 语义
 	Value  any
 	Line   int
+	Column int // 起始列 按字符计算
 }
 
 func (t *Token) String() string {
-	return fmt.Sprintf("type:%d,lexeme:%s,value:%v,line:%d", t.Type, t.Lexeme, t.Value, t.Line)
+	return fmt.Sprintf("type:%d,lexeme:%s,value:%v,line:%d,column:%d", t.Type, t.Lexeme, t.Value, t.Line, t.Column)
 }
 
 func NewToken(type0 TokenType, lexeme string, value any, line int) *Token {
@@ -0,0 +1,19 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "fmt"
+
+type BaseTrait struct { // trait 只包含方法 通过 with 混入到类中
+	MethodTable
+}
+
+func NewBaseTrait(name string, methods map[string]*BaseCall) *BaseTrait {
+	return &BaseTrait{MethodTable: MethodTable{Name: name, Methods: methods}}
+}
+
+func (b *BaseTrait) String() string {
+	return fmt.Sprintf("<trait %s>", b.Name)
+}
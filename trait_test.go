@@ -0,0 +1,33 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestTrait(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "mixin", Source: `trait Greet { hi() { return "hi " + this.name; } } class A with Greet { init() { this.name = "a"; } } print A().hi(); print Greet;`,
+			Output: "hi a\n<trait Greet>\n"},
+		{Name: "parent after trait", Source: `class Base { who() { return "base"; } } trait T { who() { return "T>" + super.who(); } } class A < Base with T {} print A().who();`,
+			Output: "T>base\n"},
+		{Name: "stacked", Source: `class Base { log() { return "base"; } }
+trait Stamp { log() { return "stamp>" + super.log(); } }
+trait Upper { log() { return "upper>" + super.log(); } }
+class A < Base with Stamp, Upper {} print A().log();`, Output: "upper>stamp>base\n"},
+		{Name: "stacked in template", Source: `trait T { m() { return "T"; } }
+trait U { m() { return "U>${super.m()}"; } }
+class A with T, U {} print A().m();`, Output: "U>T\n"},
+		{Name: "stacked in nested func", Source: `trait T1 { m() { return "1"; } }
+trait T2 { m() { func f() { return super.m(); } return "2>" + f(); } }
+class A with T1, T2 {} print A().m();`, Output: "2>1\n"},
+		{Name: "conflict", Source: `trait T1 { m() { return 1; } } trait T2 { m() { return 2; } } class A with T1, T2 {}`,
+			Err: "class A method m conflict between trait T1 and T2"},
+		{Name: "super other name", Source: `trait T1 { m() { return 1; } } trait T2 { m() { return super.n(); } } class A with T1, T2 {}`,
+			Err: "class A method m conflict between trait T1 and T2"},
+		{Name: "class resolves", Source: `trait T1 { m() { return 1; } } trait T2 { m() { return 2; } } class A with T1, T2 { m() { return 3; } } print A().m();`,
+			Output: "3\n"},
+		{Name: "not a trait", Source: "class B {}\nclass A with B {}", Err: "[line 2] runtime error : B not a trait"},
+	})
+}
@@ -4,7 +4,13 @@
 */
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode"
+)
 
 func HandleErr(err error) {
 	if err != nil {
@@ -13,23 +19,103 @@ func HandleErr(err error) {
 }
 
 func Report(line int, where string, msg string) {
-	fmt.Printf("[line %d] Error %s : %s\n", line, where, msg)
+	fmt.Fprintf(Output, "[line %d] Error %s : %s\n", line, where, msg) // 与 print 输出到同一位置
 }
 
 func Error(line int, msg string) {
 	Report(line, "", msg)
 }
 
-func IsDigit(ch uint8) bool {
+func RuntimeError(token *Token, msg string) { // 运行时错误 带上出错位置
+	panic(fmt.Sprintf("[line %d] runtime error : %s", token.Line, msg))
+}
+
+func MustCond(token *Token, val any, what string) bool { // 条件只接受布尔值
+	res, ok := val.(bool)
+	if !ok {
+		RuntimeError(token, fmt.Sprintf("%s must be bool, got %s", what, TypeName(val)))
+	}
+	return res
+}
+
+func IsDigit(ch rune) bool { // 数字字面量只支持 ascii 数字
 	return ch >= '0' && ch <= '9'
 }
 
-func IsAlpha(ch uint8) bool {
-	if ch >= 'a' && ch <= 'z' {
-		return true
+func IsAlpha(ch rune) bool { // 与 go 标识符规则一致 支持 unicode 字母
+	return ch == '_' || unicode.IsLetter(ch)
+}
+
+func IsAlphaNum(ch rune) bool { // 标识符非首字符 支持 unicode 数字
+	return IsAlpha(ch) || unicode.IsDigit(ch)
+}
+
+func ToString(val any) string { // print 字符串拼接 插值字符串 str() 共用的文本转换
+	switch temp := val.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return temp
+	case float64: // 整数值的小数不输出 .0
+		if math.Abs(temp) < 1e21 && (temp == 0 || math.Abs(temp) >= 1e-6) {
+			return strconv.FormatFloat(temp, 'f', -1, 64)
+		}
+		return strconv.FormatFloat(temp, 'g', -1, 64)
+	case *BaseInstance: // 实例可以通过 toString 方法自定义输出
+		if method := temp.Class.FindMethod("toString"); method != nil {
+			return ToString(method.BindThis(temp).Call(nil))
+		}
+		if temp.IsValue() {
+			return ValueString(temp)
+		}
+		return fmt.Sprintf("%s instance", temp.Class.Name)
+	default: // 其余类型 数字 布尔 函数 类 使用默认格式或 String 方法
+		return fmt.Sprint(val)
 	}
-	if ch >= 'A' && ch <= 'Z' {
-		return true
+}
+
+func ReprString(val any) string { // 容器与值类型中的元素 字符串带引号 避免 2 与 "2" 混淆
+	if str, ok := val.(string); ok {
+		return strconv.Quote(str)
+	}
+	return ToString(val)
+}
+
+func TypeName(val any) string { // 运行时类型名称 用于错误提示
+	switch temp := val.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case *big.Int:
+		return "bigint"
+	case *Decimal:
+		return "decimal"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	case *HashSet:
+		return "set"
+	case *Range:
+		return "range"
+	case *BaseClass:
+		return "class"
+	case *BaseTrait:
+		return "trait"
+	case *BaseInterface:
+		return "interface"
+	case *BaseInstance:
+		return temp.Class.Name
+	case ICall:
+		return "function"
+	default:
+		return fmt.Sprintf("%T", val)
 	}
-	return ch == '_'
 }
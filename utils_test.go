@@ -0,0 +1,21 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestToString(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "values", Source: `print nil; print 2.0; print 1.5; print true; print "s";`, Output: "nil\n2\n1.5\ntrue\ns\n"},
+		{Name: "callables", Source: `func f() {} class A {} print f; print A; print clock;`,
+			Output: "<fn f>\n<class A>\n<native fn clock>\n"},
+		{Name: "instance", Source: `class A {} print A();`, Output: "A instance\n"},
+		{Name: "toString", Source: `class P { init(x) { this.x = x; } toString() { return "P(" + str(this.x) + ")"; } }
+var p = P(1); print p; print "p = " + p; print str(p);`, Output: "P(1)\np = P(1)\nP(1)\n"},
+		{Name: "toString default param", Source: `class A { toString(sep = ",") { return "a" + sep; } } print A();`, Output: "a,\n"},
+		{Name: "toString params", Source: "class A {\n toString(x) { return x; } }", Err: "[line 2] toString must take no params"},
+		{Name: "trait toString params", Source: `trait T { toString(x) { return x; } }`, Err: "toString must take no params"},
+	})
+}
@@ -0,0 +1,85 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 值类型的实例 枚举成员与 record 按字段比较 哈希 打印
+// 类的 ValueFields 不为 nil 时为值类型，字段按声明顺序由生成的构造方法赋值
+
+func (b *BaseInstance) IsValue() bool {
+	return b.Class.ValueFields != nil
+}
+
+func ValueEqual(left, right *BaseInstance) bool { // 同一个类且字段都相等
+	if left.Class != right.Class {
+		return false
+	}
+	for _, name := range left.Class.ValueFields {
+		if !Equal(left.Fields[name], right.Fields[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+type ValueKey struct { // 值类型实例作为 map 键
+	Class  *BaseClass
+	Fields string // 各字段 HashKey 的文本 带类型避免 1 与 "1" 冲突
+}
+
+func NewValueKey(token *Token, inst *BaseInstance) ValueKey {
+	items := make([]string, 0, len(inst.Class.ValueFields))
+	for _, name := range inst.Class.ValueFields {
+		key := HashKey(token, inst.Fields[name])
+		items = append(items, fmt.Sprintf("%T %#v", key, key))
+	}
+	return ValueKey{Class: inst.Class, Fields: strings.Join(items, ",")}
+}
+
+type WithCall struct { // p.with(x: 3) 复制值类型实例 替换传入的字段
+	Inst *BaseInstance
+}
+
+func NewWithCall(inst *BaseInstance) *WithCall {
+	return &WithCall{Inst: inst}
+}
+
+func (w *WithCall) Call(args []any) any {
+	res := NewBaseInstance(w.Inst.Class)
+	for i, name := range w.Inst.Class.ValueFields {
+		res.Fields[name] = w.Inst.Fields[name]
+		if i < len(args) && args[i] != MissingArg {
+			res.Fields[name] = args[i]
+		}
+	}
+	return res
+}
+
+func (w *WithCall) ArgsSize() *Arity { // 所有字段都可以不传
+	return NewArity(0, len(w.Inst.Class.ValueFields))
+}
+
+func (w *WithCall) ParamNames() []string {
+	return w.Inst.Class.ValueFields
+}
+
+func (w *WithCall) String() string {
+	return fmt.Sprintf("<native fn %s.with>", w.Inst.Class.Name)
+}
+
+func ValueString(inst *BaseInstance) string { // Name(x: 1, y: 2) 没有字段的枚举成员只输出名称
+	if len(inst.Class.ValueFields) == 0 {
+		return inst.Class.Name
+	}
+	items := make([]string, 0, len(inst.Class.ValueFields))
+	for _, name := range inst.Class.ValueFields {
+		items = append(items, fmt.Sprintf("%s: %s", name, ReprString(inst.Fields[name])))
+	}
+	return fmt.Sprintf("%s(%s)", inst.Class.Name, strings.Join(items, ", "))
+}
@@ -0,0 +1,40 @@
+/*
+@author: sk
+@date: 2026/10/19
+*/
+package main
+
+import "testing"
+
+func TestEnum(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "plain", Source: `enum Color { Red, Green, Blue } print Color.Red; print Color.Red == Color.Red; print Color.Red == Color.Blue; for (var c in Color) { print c; }`,
+			Output: "Color.Red\ntrue\nfalse\nColor.Red\nColor.Green\nColor.Blue\n"},
+		{Name: "payload", Source: `enum Shape { Circle(r), Rect(w, h) } var a = Shape.Rect(1, 2); print a; print a.w; print a == Shape.Rect(1, 2); print a == Shape.Rect(2, 1); print a is Shape;`,
+			Output: "Shape.Rect(w: 1, h: 2)\n1\ntrue\nfalse\ntrue\n"},
+		{Name: "map key", Source: `enum Shape { Circle(r) } var m = {Shape.Circle(1): "one"}; print m[Shape.Circle(1)];`, Output: "one\n"},
+		{Name: "method", Source: `enum Color { Red, Green; label() { return "c" + className(this); } } print Color.Green.label();`, Output: "cColor.Green\n"},
+		{Name: "repeat", Source: `enum Color { Red, Red }`, Err: "[line 1] runtime error : repeat variant Red in enum Color"},
+		{Name: "immutable", Source: `enum Color { Red } Color.Red.x = 1;`, Err: "can't set field x of Color.Red, use with to copy"},
+		{Name: "replace variant", Source: "enum Color { Red }\nColor.Red = 5;", Err: "[line 2] runtime error : can't set member Red of enum Color"},
+		{Name: "add variant", Source: "enum Color { Red }\nColor.Purple = 3;", Err: "[line 2] runtime error : can't set member Purple of enum Color"},
+		{Name: "arity", Source: `enum Shape { Circle(r) } Shape.Circle();`, Err: "func <class Shape.Circle> args not match 0, need 1"},
+	})
+}
+
+func TestRecord(t *testing.T) {
+	RunLoxCases(t, []*LoxCase{
+		{Name: "basic", Source: `record Point(x, y) { sum() { return this.x + this.y; } } var p = Point(1, 2); print p; print p.sum(); print p == Point(1, 2); print p == Point(2, 1);`,
+			Output: "Point(x: 1, y: 2)\n3\ntrue\nfalse\n"},
+		{Name: "with", Source: `record Point(x, y); var p = Point(1, 2); print p.with(x: 3); print p;`, Output: "Point(x: 3, y: 2)\nPoint(x: 1, y: 2)\n"},
+		{Name: "string field", Source: `record Name(n); print Name("a");`, Output: "Name(n: \"a\")\n"},
+		{Name: "hash", Source: `record Point(x, y); var m = {Point(1, 2): "a"}; print m[Point(1, 2)]; var s = set(); s.add(Point(1, 2)); s.add(Point(1, 2)); print s.len();`,
+			Output: "a\n1\n"},
+		{Name: "immutable", Source: `record Point(x, y); var p = Point(1, 2); p.x = 5;`, Err: "can't set field x of Point, use with to copy"},
+		{Name: "repeat", Source: `record Point(x, x);`, Err: "[line 1] runtime error : repeat field x in record Point"},
+		{Name: "replace variant", Source: "enum Color { Red }\nColor.Red = 5;", Err: "[line 2] runtime error : can't set member Red of enum Color"},
+		{Name: "add variant", Source: "enum Color { Red }\nColor.Purple = 3;", Err: "[line 2] runtime error : can't set member Purple of enum Color"},
+		{Name: "arity", Source: `record Point(x, y); Point(1);`, Err: "func <class Point> args not match 1, need 2"},
+		{Name: "with unknown", Source: `record Point(x, y); print Point(1, 2).with(z: 1);`, Err: "has no param named z"},
+	})
+}